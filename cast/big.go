@@ -0,0 +1,266 @@
+package cast
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	bigIntTarget        = "*big.Int"
+	bigFloatTarget      = "*big.Float"
+	decimalStringTarget = "decimal string"
+)
+
+// TryBigInt casts value to *big.Int. Strings may be in any base supported by strconv.ParseInt
+// with base 0 or be decimals with fraction or exponent, fractions are handled according to
+// the conversion policy (see WithPolicy). The result is always a new value
+func TryBigInt(value interface{}, opts ...Option) (*big.Int, error) {
+	o := newOptions(opts)
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case big.Int:
+		return new(big.Int).Set(&castedVal), nil
+	case big.Float:
+		if castedVal.IsInf() {
+			return nil, overflowError(value, bigIntTarget)
+		}
+		r, _ := castedVal.Rat(nil)
+		return ratToBigInt(value, r, o)
+	case big.Rat:
+		return ratToBigInt(value, &castedVal, o)
+	case int, int64, int32, int16, int8:
+		n, _ := TryInt64(castedVal)
+		return big.NewInt(n), nil
+	case uint, uint64, uint32, uint16, uint8:
+		n, _ := TryUInt64(castedVal)
+		return new(big.Int).SetUint64(n), nil
+	case float64:
+		return floatToBigInt(value, castedVal, o)
+	case float32:
+		return floatToBigInt(value, float64(castedVal), o)
+	case string, []byte:
+		text, err := o.numberText(value, false, bigIntTarget)
+		if err != nil {
+			return nil, err
+		}
+		return parseBigInt(value, text, o)
+	case json.Number:
+		return parseBigInt(value, string(castedVal), o)
+	default:
+		converted, ok, err := fallback(value, bigIntTarget)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, unsupportedError(value, bigIntTarget)
+		}
+		return TryBigInt(converted, opts...)
+	}
+}
+
+// TryBigFloat casts value to *big.Float. Integers are converted exactly,
+// strings are parsed with precision enough for all their digits
+func TryBigFloat(value interface{}, opts ...Option) (*big.Float, error) {
+	o := newOptions(opts)
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case big.Float:
+		return new(big.Float).Copy(&castedVal), nil
+	case big.Int:
+		return new(big.Float).SetInt(&castedVal), nil
+	case big.Rat:
+		return new(big.Float).SetRat(&castedVal), nil
+	case int, int64, int32, int16, int8:
+		n, _ := TryInt64(castedVal)
+		return new(big.Float).SetInt64(n), nil
+	case uint, uint64, uint32, uint16, uint8:
+		n, _ := TryUInt64(castedVal)
+		return new(big.Float).SetUint64(n), nil
+	case float64:
+		return floatToBigFloat(value, castedVal)
+	case float32:
+		return floatToBigFloat(value, float64(castedVal))
+	case string, []byte:
+		text, err := o.numberText(value, false, bigFloatTarget)
+		if err != nil {
+			return nil, err
+		}
+		return parseBigFloat(value, text)
+	case json.Number:
+		return parseBigFloat(value, string(castedVal))
+	default:
+		converted, ok, err := fallback(value, bigFloatTarget)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, unsupportedError(value, bigFloatTarget)
+		}
+		return TryBigFloat(converted, opts...)
+	}
+}
+
+// TryDecimalString casts value to a decimal string without exponent like "-12345.678".
+// Strings, json.Number and big numbers are converted exactly, floats are formatted
+// with the shortest representation which parses back to the same float
+func TryDecimalString(value interface{}, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case big.Int:
+		return castedVal.String(), nil
+	case big.Float:
+		if castedVal.IsInf() {
+			return "", overflowError(value, decimalStringTarget)
+		}
+		return castedVal.Text('f', -1), nil
+	case big.Rat:
+		return ratToDecimal(value, &castedVal)
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		return TryString(castedVal)
+	case float64:
+		return floatToDecimal(value, castedVal, 64)
+	case float32:
+		return floatToDecimal(value, float64(castedVal), 32)
+	case string, []byte:
+		text, err := o.numberText(value, false, decimalStringTarget)
+		if err != nil {
+			return "", err
+		}
+		return parseDecimal(value, text)
+	case json.Number:
+		return parseDecimal(value, string(castedVal))
+	default:
+		converted, ok, err := fallback(value, decimalStringTarget)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", unsupportedError(value, decimalStringTarget)
+		}
+		return TryDecimalString(converted, opts...)
+	}
+}
+
+func parseBigInt(value interface{}, s string, o *options) (*big.Int, error) {
+	trimmed := strings.TrimSpace(s)
+	if n, ok := new(big.Int).SetString(trimmed, 0); ok {
+		return n, nil
+	}
+
+	r, ok := parseRat(trimmed)
+	if !ok {
+		return nil, syntaxError(value, bigIntTarget, "invalid number")
+	}
+	return ratToBigInt(value, r, o)
+}
+
+func floatToBigInt(value interface{}, f float64, o *options) (*big.Int, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, overflowError(value, bigIntTarget)
+	}
+	return ratToBigInt(value, new(big.Rat).SetFloat64(f), o)
+}
+
+// ratToBigInt converts r to integer according to the policy
+func ratToBigInt(value interface{}, r *big.Rat, o *options) (*big.Int, error) {
+	if r.IsInt() {
+		return new(big.Int).Set(r.Num()), nil
+	}
+
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	switch o.policy {
+	case PolicyStrict:
+		return nil, newError(value, bigIntTarget, KindPrecisionLoss, nil)
+	case PolicyRound:
+		// compare the doubled remainder with the denominator to round half to even
+		cmp := new(big.Int).Abs(rem)
+		cmp.Lsh(cmp, 1)
+		if c := cmp.Cmp(r.Denom()); c > 0 || c == 0 && quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(int64(r.Sign())))
+		}
+		return quo, nil
+	default:
+		return quo, nil
+	}
+}
+
+func floatToBigFloat(value interface{}, f float64) (*big.Float, error) {
+	if math.IsNaN(f) {
+		return nil, syntaxError(value, bigFloatTarget, "NaN")
+	}
+	return new(big.Float).SetFloat64(f), nil
+}
+
+func parseBigFloat(value interface{}, s string) (*big.Float, error) {
+	trimmed := strings.TrimSpace(s)
+	// 4 bits per digit is more than enough for every digit, 64 bits cover short inputs
+	prec := uint(len(trimmed))*4 + 64
+	f, _, err := big.ParseFloat(trimmed, 0, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, newError(value, bigFloatTarget, KindSyntax, err)
+	}
+	return f, nil
+}
+
+func floatToDecimal(value interface{}, f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", overflowError(value, decimalStringTarget)
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize), nil
+}
+
+func parseDecimal(value interface{}, s string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+	if n, ok := new(big.Int).SetString(trimmed, 0); ok {
+		return n.String(), nil
+	}
+
+	r, ok := parseRat(trimmed)
+	if !ok {
+		return "", syntaxError(value, decimalStringTarget, "invalid number")
+	}
+	return ratToDecimal(value, r)
+}
+
+// ratToDecimal formats r exactly, that's possible only if its denominator has no factors other than 2 and 5
+func ratToDecimal(value interface{}, r *big.Rat) (string, error) {
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+
+	denom := new(big.Int).Set(r.Denom())
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	twos, fives := 0, 0
+	for rem.Mod(denom, two).Sign() == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for rem.Mod(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", newError(value, decimalStringTarget, KindPrecisionLoss, nil)
+	}
+
+	decimals := twos
+	if fives > decimals {
+		decimals = fives
+	}
+	return r.FloatString(decimals), nil
+}
+
+// parseRat parses decimal numbers with fraction and exponent, fractions like "1/3" are rejected
+func parseRat(s string) (*big.Rat, bool) {
+	if strings.Contains(s, "/") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
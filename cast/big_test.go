@@ -0,0 +1,156 @@
+package cast
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryBigInt(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	testTryBigInt(t, huge,
+		"123456789012345678901234567890",
+		" 123456789012345678901234567890 ",
+		json.Number("123456789012345678901234567890"),
+		huge,
+		*huge,
+		"1.2345678901234567890123456789e29",
+	)
+	testTryBigInt(t, big.NewInt(-42), -42, int8(-42), "-42", "-0x2a", -42.0, float32(-42), json.Number("-42"), "-42.9")
+	testTryBigInt(t, new(big.Int).SetUint64(math.MaxUint64), uint64(math.MaxUint64), "18446744073709551615")
+	testTryBigInt(t, big.NewInt(1e15), "1e15", 1e15)
+
+	casted, err := TryBigInt(huge)
+	require.NoError(t, err)
+	casted.Add(casted, big.NewInt(1))
+	require.Equal(t, "123456789012345678901234567890", huge.String())
+
+	rounded, err := TryBigInt("2.5", WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2), rounded)
+
+	rounded, err = TryBigInt("-3.5", WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(-4), rounded)
+
+	rounded, err = TryBigInt(2.6, WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3), rounded)
+
+	_, err = TryBigInt("2.5", WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryBigInt("abc")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryBigInt("1/3")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryBigInt(math.Inf(1))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryBigInt(struct{}{})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+}
+
+func Test_TryBigFloat(t *testing.T) {
+	f, err := TryBigFloat("123456789012345678901234567890.5")
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890.5", f.Text('f', -1))
+
+	f, err = TryBigFloat(int64(math.MaxInt64))
+	require.NoError(t, err)
+	require.Equal(t, "9223372036854775807", f.Text('f', -1))
+
+	f, err = TryBigFloat(0.25)
+	require.NoError(t, err)
+	require.Equal(t, "0.25", f.Text('f', -1))
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	f, err = TryBigFloat(huge)
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890", f.Text('f', -1))
+
+	_, err = TryBigFloat("12,5")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryBigFloat(math.NaN())
+	require.Error(t, err)
+}
+
+func Test_TryDecimalString(t *testing.T) {
+	testTryDecimalString(t, "12345678901234567890.123456789",
+		"12345678901234567890.123456789",
+		json.Number("12345678901234567890.123456789"),
+		"1.2345678901234567890123456789e19",
+		"12345678901234567890.1234567890000",
+	)
+	testTryDecimalString(t, "0.1", 0.1, "0.1", ".1", "1e-1", big.NewRat(1, 10))
+	testTryDecimalString(t, "-42", -42, "-42", "-42.000", "-0x2a", big.NewInt(-42))
+	testTryDecimalString(t, "1500", "1.5e3", uint16(1500), 1500.0, float32(1500))
+	testTryDecimalString(t, "0.5", float32(0.5), new(big.Float).SetFloat64(0.5))
+	testTryDecimalString(t, "18446744073709551615", uint64(math.MaxUint64))
+
+	_, err := TryDecimalString(big.NewRat(1, 3))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryDecimalString("1.2.3")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryDecimalString(math.NaN())
+	require.Error(t, err)
+}
+
+func Test_BigIntToBuiltin(t *testing.T) {
+	n, err := TryInt64(big.NewInt(-42))
+	require.NoError(t, err)
+	require.Equal(t, int64(-42), n)
+
+	u, err := TryUInt64(new(big.Int).SetUint64(math.MaxUint64))
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), u)
+
+	_, err = TryInt64(new(big.Int).SetUint64(math.MaxUint64))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryUInt8(big.NewInt(256))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryUInt64(big.NewInt(-1))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	f, err := TryFloat64(big.NewInt(1 << 53))
+	require.NoError(t, err)
+	require.Equal(t, float64(1<<53), f)
+
+	_, err = TryFloat64(big.NewInt(1<<53+1), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryFloat32(new(big.Int).Exp(big.NewInt(10), big.NewInt(40), nil))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	s, err := TryString(big.NewInt(42))
+	require.NoError(t, err)
+	require.Equal(t, "42", s)
+}
+
+func testTryBigInt(t *testing.T, expected *big.Int, in ...interface{}) {
+	for _, inVal := range in {
+		casted, err := TryBigInt(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, 0, expected.Cmp(casted), "%#v: expected %s, got %s", inVal, expected, casted)
+	}
+}
+
+func testTryDecimalString(t *testing.T, expected string, in ...interface{}) {
+	for _, inVal := range in {
+		casted, err := TryDecimalString(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, expected, casted, "%#v", inVal)
+	}
+}
@@ -0,0 +1,78 @@
+package cast
+
+import (
+	"strings"
+)
+
+var (
+	defaultTruthy = []string{"1", "t", "true", "y", "yes", "on"}
+	defaultFalsy  = []string{"0", "f", "false", "n", "no", "off"}
+)
+
+// TryBool casts value to bool. Numbers are true when not equal to zero,
+// strings are matched against truthy and falsy vocabularies (see WithBoolStrings)
+func TryBool(value interface{}, opts ...Option) (bool, error) {
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case bool:
+		return castedVal, nil
+	case int:
+		return castedVal != 0, nil
+	case int64:
+		return castedVal != 0, nil
+	case int32:
+		return castedVal != 0, nil
+	case int16:
+		return castedVal != 0, nil
+	case int8:
+		return castedVal != 0, nil
+	case uint:
+		return castedVal != 0, nil
+	case uint64:
+		return castedVal != 0, nil
+	case uint32:
+		return castedVal != 0, nil
+	case uint16:
+		return castedVal != 0, nil
+	case uint8:
+		return castedVal != 0, nil
+	case float64:
+		return castedVal != 0, nil
+	case float32:
+		return castedVal != 0, nil
+	case string:
+		return parseBool(value, castedVal, newOptions(opts))
+	case []byte:
+		return parseBool(value, string(castedVal), newOptions(opts))
+	default:
+		converted, ok, err := fallback(value, "bool")
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, unsupportedError(value, "bool")
+		}
+		return TryBool(converted, opts...)
+	}
+}
+
+func parseBool(value interface{}, s string, o *options) (bool, error) {
+	trimmed := strings.TrimSpace(s)
+	if containsFold(o.truthy, trimmed) {
+		return true, nil
+	}
+	if containsFold(o.falsy, trimmed) {
+		return false, nil
+	}
+	return false, syntaxError(value, "bool", "unknown boolean string")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package cast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryBool(t *testing.T) {
+	// Bool true cases
+	testTryBool(t, true, true, 1, int64(-1), uint8(1), 0.5, float32(1), "1", "true", "TRUE", " yes ", "On", "t", []byte("y"))
+
+	// Bool false cases
+	testTryBool(t, false, false, 0, int64(0), uint8(0), 0.0, float32(0), "0", "false", "False", "no", "OFF", "f", []byte("n"))
+
+	// Bool error cases
+	testTryBoolErr(t, "", "maybe", "2", struct{}{}, nil)
+
+	b := true
+	pb := &b
+	casted, err := TryBool(&pb)
+	require.NoError(t, err)
+	require.True(t, casted)
+
+	casted, err = TryBool("да", WithBoolStrings([]string{"да"}, []string{"нет"}))
+	require.NoError(t, err)
+	require.True(t, casted)
+
+	casted, err = TryBool("НЕТ", WithBoolStrings([]string{"да"}, []string{"нет"}))
+	require.NoError(t, err)
+	require.False(t, casted)
+
+	_, err = TryBool("yes", WithBoolStrings([]string{"да"}, nil))
+	require.Error(t, err)
+
+	casted, err = TryBool("off", WithBoolStrings([]string{"да"}, nil))
+	require.NoError(t, err)
+	require.False(t, casted)
+}
+
+func testTryBool(t *testing.T, expected bool, in ...interface{}) {
+	for _, inVal := range in {
+		casted, err := TryBool(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, expected, casted, "%#v", inVal)
+	}
+}
+
+func testTryBoolErr(t *testing.T, in ...interface{}) {
+	for _, inVal := range in {
+		_, err := TryBool(inVal)
+		require.Error(t, err, "%#v", inVal)
+	}
+}
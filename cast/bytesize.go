@@ -0,0 +1,114 @@
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const byteSizeTarget = "byte size"
+
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"e":   1e18,
+	"eb":  1e18,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+	"ei":  1 << 60,
+	"eib": 1 << 60,
+}
+
+// TryByteSize casts value to number of bytes. Strings may have decimal (KB, MB, ...)
+// or binary (KiB, MiB, ...) unit suffix, e.g. "10MiB" or "1.5GB", numbers are bytes.
+// The number before the unit follows the format set by WithNumberFormat, e.g. "1 536 KiB"
+func TryByteSize(value interface{}, opts ...Option) (uint64, error) {
+	value = indirect(value)
+	o := newOptions(opts)
+
+	switch castedVal := value.(type) {
+	case string:
+		return parseByteSize(value, castedVal, o.numberFormat)
+	case []byte:
+		return parseByteSize(value, string(castedVal), o.numberFormat)
+	default:
+		size, err := TryUInt64(value, opts...)
+		if castErr, ok := err.(*Error); ok {
+			castErr.TargetType = byteSizeTarget
+		}
+		return size, err
+	}
+}
+
+func parseByteSize(value interface{}, s string, format *NumberFormat) (uint64, error) {
+	trimmed := strings.TrimSpace(s)
+
+	i := strings.IndexFunc(trimmed, func(r rune) bool {
+		return !isByteSizeNumberRune(r, format)
+	})
+	if i < 0 {
+		i = len(trimmed)
+	}
+	if strings.TrimSpace(trimmed[:i]) == "" {
+		return 0, syntaxError(value, byteSizeTarget, "no number found")
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(trimmed[i:]))]
+	if !ok {
+		return 0, syntaxError(value, byteSizeTarget, "unknown unit "+strconv.Quote(strings.TrimSpace(trimmed[i:])))
+	}
+
+	number := trimmed[:i]
+	if format != nil {
+		normalized, err := format.normalize(value, number, false, byteSizeTarget)
+		if err != nil {
+			return 0, err
+		}
+		number = normalized
+	}
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > math.MaxUint64/unit {
+			return 0, overflowError(value, byteSizeTarget)
+		}
+		return n * unit, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, parseError(value, byteSizeTarget, err)
+	}
+	size := f * float64(unit)
+	if size >= math.MaxUint64 {
+		return 0, overflowError(value, byteSizeTarget)
+	}
+	return uint64(size), nil
+}
+
+// isByteSizeNumberRune reports whether r belongs to the number preceding the unit
+func isByteSizeNumberRune(r rune, format *NumberFormat) bool {
+	if r >= '0' && r <= '9' || r == '.' {
+		return true
+	}
+	if format == nil {
+		return false
+	}
+	return r == format.DecimalSeparator || format.isThousandsSeparator(r) || format.AllowUnderscores && r == '_'
+}
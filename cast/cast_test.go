@@ -582,6 +582,10 @@ func Test_TryDateTime(t *testing.T) {
 
 	timeStr = now.Format(time.RFC3339Nano)
 	parsed, err = TryDateTime(timeStr)
+	require.NoError(t, err)
+	require.True(t, now.Equal(parsed))
+
+	_, err = TryDateTime("not a time")
 	require.Error(t, err)
 }
 
@@ -598,7 +602,15 @@ func Test_TryDate(t *testing.T) {
 
 	dateStr = now.Format(time.RFC3339Nano)
 	parsed, err = TryDate(dateStr)
+	require.NoError(t, err)
+	require.True(t, now.Equal(parsed))
+
+	_, err = TryDate("27.03.2019")
 	require.Error(t, err)
+
+	parsed, err = TryDate("27.03.2019", WithLayouts("02.01.2006"))
+	require.NoError(t, err)
+	require.Equal(t, "2019-03-27", parsed.Format(dateFormat))
 }
 
 func testTryUInt8Err(t *testing.T, in ...interface{}) {
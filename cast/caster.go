@@ -0,0 +1,135 @@
+package cast
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Caster applies the same options to every conversion, options passed to a method are applied after them
+type Caster struct {
+	opts []Option
+}
+
+// NewCaster returns Caster with options applied to every conversion
+func NewCaster(opts ...Option) *Caster {
+	return &Caster{opts: opts}
+}
+
+func (c *Caster) with(opts []Option) []Option {
+	if c == nil || len(c.opts) == 0 {
+		return opts
+	}
+	merged := make([]Option, 0, len(c.opts)+len(opts))
+	merged = append(merged, c.opts...)
+	return append(merged, opts...)
+}
+
+func (c *Caster) TryUInt8(value interface{}, opts ...Option) (uint8, error) {
+	return TryUInt8(value, c.with(opts)...)
+}
+
+func (c *Caster) TryUInt16(value interface{}, opts ...Option) (uint16, error) {
+	return TryUInt16(value, c.with(opts)...)
+}
+
+func (c *Caster) TryUInt32(value interface{}, opts ...Option) (uint32, error) {
+	return TryUInt32(value, c.with(opts)...)
+}
+
+func (c *Caster) TryUInt64(value interface{}, opts ...Option) (uint64, error) {
+	return TryUInt64(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInt8(value interface{}, opts ...Option) (int8, error) {
+	return TryInt8(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInt16(value interface{}, opts ...Option) (int16, error) {
+	return TryInt16(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInt32(value interface{}, opts ...Option) (int32, error) {
+	return TryInt32(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInt64(value interface{}, opts ...Option) (int64, error) {
+	return TryInt64(value, c.with(opts)...)
+}
+
+func (c *Caster) TryFloat32(value interface{}, opts ...Option) (float32, error) {
+	return TryFloat32(value, c.with(opts)...)
+}
+
+func (c *Caster) TryFloat64(value interface{}, opts ...Option) (float64, error) {
+	return TryFloat64(value, c.with(opts)...)
+}
+
+func (c *Caster) TryBool(value interface{}, opts ...Option) (bool, error) {
+	return TryBool(value, c.with(opts)...)
+}
+
+func (c *Caster) TryDuration(value interface{}, opts ...Option) (time.Duration, error) {
+	return TryDuration(value, c.with(opts)...)
+}
+
+func (c *Caster) TryDate(value interface{}, opts ...Option) (time.Time, error) {
+	return TryDate(value, c.with(opts)...)
+}
+
+func (c *Caster) TryDateTime(value interface{}, opts ...Option) (time.Time, error) {
+	return TryDateTime(value, c.with(opts)...)
+}
+
+func (c *Caster) TryByteSize(value interface{}, opts ...Option) (uint64, error) {
+	return TryByteSize(value, c.with(opts)...)
+}
+
+func (c *Caster) TryString(value interface{}) (string, error) {
+	return TryString(value)
+}
+
+func (c *Caster) TryUUID(value interface{}, opts ...Option) (string, error) {
+	return TryUUID(value, c.with(opts)...)
+}
+
+func (c *Caster) TryUUIDValue(value interface{}, opts ...Option) (uuid.UUID, error) {
+	return TryUUIDValue(value, c.with(opts)...)
+}
+
+func (c *Caster) TryStringSlice(value interface{}, opts ...Option) ([]string, error) {
+	return TryStringSlice(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInt64Slice(value interface{}, opts ...Option) ([]int64, error) {
+	return TryInt64Slice(value, c.with(opts)...)
+}
+
+func (c *Caster) TryFloat64Slice(value interface{}, opts ...Option) ([]float64, error) {
+	return TryFloat64Slice(value, c.with(opts)...)
+}
+
+func (c *Caster) TryStringMap(value interface{}, opts ...Option) (map[string]interface{}, error) {
+	return TryStringMap(value, c.with(opts)...)
+}
+
+func (c *Caster) TryStringMapString(value interface{}, opts ...Option) (map[string]string, error) {
+	return TryStringMapString(value, c.with(opts)...)
+}
+
+func (c *Caster) TryInto(value interface{}, out interface{}, opts ...Option) error {
+	return TryInto(value, out, c.with(opts)...)
+}
+
+func (c *Caster) TryBigInt(value interface{}, opts ...Option) (*big.Int, error) {
+	return TryBigInt(value, c.with(opts)...)
+}
+
+func (c *Caster) TryBigFloat(value interface{}, opts ...Option) (*big.Float, error) {
+	return TryBigFloat(value, c.with(opts)...)
+}
+
+func (c *Caster) TryDecimalString(value interface{}, opts ...Option) (string, error) {
+	return TryDecimalString(value, c.with(opts)...)
+}
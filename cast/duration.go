@@ -0,0 +1,159 @@
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const durationTarget = "time.Duration"
+
+// TryDuration casts value to time.Duration. Accepted strings are Go durations ("1h30m"),
+// ISO 8601 durations ("PT5M", "P1DT2H") and plain numbers. Numbers are counted
+// in seconds unless other unit is set with WithDurationUnit. Numbers in strings
+// follow the format set by WithNumberFormat, e.g. "1,5h" with ',' as decimal separator
+func TryDuration(value interface{}, opts ...Option) (time.Duration, error) {
+	value = indirect(value)
+	o := newOptions(opts)
+
+	switch castedVal := value.(type) {
+	case time.Duration:
+		return castedVal, nil
+	case string:
+		return parseDuration(value, castedVal, o)
+	case []byte:
+		return parseDuration(value, string(castedVal), o)
+	case float64:
+		return floatToDuration(value, castedVal, o.durationUnit)
+	case float32:
+		return floatToDuration(value, float64(castedVal), o.durationUnit)
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		n, err := TryInt64(castedVal)
+		if err != nil {
+			return 0, overflowError(value, durationTarget)
+		}
+		return intToDuration(value, n, o.durationUnit)
+	default:
+		converted, ok, err := fallback(value, durationTarget)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, unsupportedError(value, durationTarget)
+		}
+		return TryDuration(converted, opts...)
+	}
+}
+
+func parseDuration(value interface{}, s string, o *options) (time.Duration, error) {
+	trimmed := strings.TrimSpace(s)
+	iso := strings.HasPrefix(trimmed, "P") || strings.HasPrefix(trimmed, "-P")
+	if o.numberFormat != nil && !iso {
+		normalized, err := o.numberFormat.normalize(value, trimmed, false, durationTarget)
+		if err != nil {
+			return 0, err
+		}
+		trimmed = normalized
+	}
+
+	if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		return intToDuration(value, n, o.durationUnit)
+	}
+	if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return floatToDuration(value, f, o.durationUnit)
+	}
+	if iso {
+		return parseISODuration(value, trimmed)
+	}
+
+	d, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, newError(value, durationTarget, KindSyntax, err)
+	}
+	return d, nil
+}
+
+func intToDuration(value interface{}, n int64, unit time.Duration) (time.Duration, error) {
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return 0, overflowError(value, durationTarget)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func floatToDuration(value interface{}, f float64, unit time.Duration) (time.Duration, error) {
+	d := f * float64(unit)
+	if math.IsNaN(d) || d < math.MinInt64 || d >= math.MaxInt64 {
+		return 0, overflowError(value, durationTarget)
+	}
+	return time.Duration(d), nil
+}
+
+// parseISODuration parses ISO 8601 durations in form of PnWnDTnHnMnS.
+// Years and months are rejected as their length depends on a calendar
+func parseISODuration(value interface{}, s string) (time.Duration, error) {
+	rest := s
+	negative := strings.HasPrefix(rest, "-")
+	if negative {
+		rest = rest[1:]
+	}
+	rest = rest[1:] // skip P
+
+	if rest == "" || strings.HasSuffix(rest, "T") {
+		return 0, syntaxError(value, durationTarget, "invalid ISO 8601 duration")
+	}
+
+	var total float64
+	inTime := false
+	for len(rest) > 0 {
+		if rest[0] == 'T' {
+			if inTime {
+				return 0, syntaxError(value, durationTarget, "invalid ISO 8601 duration")
+			}
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.' || rest[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			return 0, syntaxError(value, durationTarget, "invalid ISO 8601 duration")
+		}
+		n, err := strconv.ParseFloat(strings.Replace(rest[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, parseError(value, durationTarget, err)
+		}
+
+		var unit time.Duration
+		switch designator := rest[i]; {
+		case designator == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case designator == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case designator == 'H' && inTime:
+			unit = time.Hour
+		case designator == 'M' && inTime:
+			unit = time.Minute
+		case designator == 'S' && inTime:
+			unit = time.Second
+		case designator == 'Y' || designator == 'M':
+			return 0, syntaxError(value, durationTarget, "years and months are not supported")
+		default:
+			return 0, syntaxError(value, durationTarget, "invalid ISO 8601 duration")
+		}
+
+		total += n * float64(unit)
+		rest = rest[i+1:]
+	}
+
+	if negative {
+		total = -total
+	}
+	if total < math.MinInt64 || total >= math.MaxInt64 {
+		return 0, overflowError(value, durationTarget)
+	}
+	return time.Duration(total), nil
+}
@@ -0,0 +1,65 @@
+package cast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryDuration(t *testing.T) {
+	testTryDuration(t, 90*time.Minute, "1h30m", "PT1H30M", "PT1.5H", "5400", 5400, int64(5400), uint16(5400), 5400.0, 90*time.Minute)
+	testTryDuration(t, 36*time.Hour, "P1DT12H", "36h")
+	testTryDuration(t, 2*7*24*time.Hour, "P2W")
+	testTryDuration(t, -5*time.Minute, "-PT5M", "-300", "-5m")
+	testTryDuration(t, 1500*time.Millisecond, "1.5", 1.5, "PT1,5S")
+
+	d, err := TryDuration(250, WithDurationUnit(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, d)
+
+	d, err = TryDuration("250", WithDurationUnit(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, d)
+
+	testTryDurationErr(t, "", "P", "PT", "P1Y", "P1M", "PT1D", "P1H", "1 hour", "abc", struct{}{}, uint64(1<<63), "9223372036854775807")
+}
+
+func Test_TryByteSize(t *testing.T) {
+	testTryByteSize(t, 10<<20, "10MiB", "10 mib", "10Mi", 10<<20)
+	testTryByteSize(t, 1500000000, "1.5GB", "1.5 g", "1500MB")
+	testTryByteSize(t, 512, "512", "512B", uint16(512), 512.0)
+	testTryByteSize(t, 1536, "1.5KiB")
+
+	testTryByteSizeErr(t, "", "MB", "10XB", "-1", -1, "1.2.3MB", "20EiB", struct{}{})
+}
+
+func testTryDuration(t *testing.T, expected time.Duration, in ...interface{}) {
+	for _, inVal := range in {
+		d, err := TryDuration(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, expected, d, "%#v", inVal)
+	}
+}
+
+func testTryDurationErr(t *testing.T, in ...interface{}) {
+	for _, inVal := range in {
+		_, err := TryDuration(inVal)
+		require.Error(t, err, "%#v", inVal)
+	}
+}
+
+func testTryByteSize(t *testing.T, expected uint64, in ...interface{}) {
+	for _, inVal := range in {
+		size, err := TryByteSize(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, expected, size, "%#v", inVal)
+	}
+}
+
+func testTryByteSizeErr(t *testing.T, in ...interface{}) {
+	for _, inVal := range in {
+		_, err := TryByteSize(inVal)
+		require.Error(t, err, "%#v", inVal)
+	}
+}
@@ -0,0 +1,125 @@
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Kind is a reason of cast failure
+type Kind int
+
+const (
+	// KindUnsupportedType means the input type can't be converted to the target type at all
+	KindUnsupportedType Kind = iota + 1
+	// KindSyntax means the input is of suitable type but its content can't be parsed
+	KindSyntax
+	// KindOverflow means the input is out of the target type range
+	KindOverflow
+	// KindPrecisionLoss means the input can't be represented by the target type exactly
+	KindPrecisionLoss
+)
+
+// Sentinel errors matching *Error of corresponding Kind with errors.Is
+var (
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrSyntax          = errors.New("invalid syntax")
+	ErrOverflow        = errors.New("desired type overflow")
+	ErrPrecisionLoss   = errors.New("precision loss")
+)
+
+func (k Kind) sentinel() error {
+	switch k {
+	case KindUnsupportedType:
+		return ErrUnsupportedType
+	case KindSyntax:
+		return ErrSyntax
+	case KindOverflow:
+		return ErrOverflow
+	case KindPrecisionLoss:
+		return ErrPrecisionLoss
+	default:
+		return nil
+	}
+}
+
+func (k Kind) String() string {
+	if err := k.sentinel(); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Error describes failed conversion of Value to TargetType
+type Error struct {
+	Value      interface{}
+	InputType  string
+	TargetType string
+	Kind       Kind
+	// Key is an index or a key of the slice element or the map entry which failed conversion
+	Key string
+	// Err is an underlying error if any, e.g. *strconv.NumError
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("unable to cast element %s of %s to %s: %s", e.Key, e.InputType, e.TargetType, e.Err)
+	}
+
+	msg := fmt.Sprintf("unable to cast %#v of type %s to %s: %s", e.Value, e.InputType, e.TargetType, e.Kind)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of e.Kind
+func (e *Error) Is(target error) bool {
+	return target != nil && target == e.Kind.sentinel()
+}
+
+func newError(value interface{}, target string, kind Kind, err error) *Error {
+	return &Error{
+		Value:      value,
+		InputType:  fmt.Sprintf("%T", value),
+		TargetType: target,
+		Kind:       kind,
+		Err:        err,
+	}
+}
+
+func unsupportedError(value interface{}, target string) error {
+	return newError(value, target, KindUnsupportedType, nil)
+}
+
+func overflowError(value interface{}, target string) error {
+	return newError(value, target, KindOverflow, nil)
+}
+
+func syntaxError(value interface{}, target string, reason string) error {
+	return newError(value, target, KindSyntax, errors.New(reason))
+}
+
+// parseError wraps strconv error, out of range errors are reported as overflow
+func parseError(value interface{}, target string, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return newError(value, target, KindOverflow, err)
+	}
+	return newError(value, target, KindSyntax, err)
+}
+
+// elementError reports failed conversion of an element with key of the value, kind is taken from err
+func elementError(value interface{}, target string, key string, err error) error {
+	kind := KindUnsupportedType
+	if castErr, ok := err.(*Error); ok {
+		kind = castErr.Kind
+	}
+	e := newError(value, target, kind, err)
+	e.Key = key
+	return e
+}
@@ -0,0 +1,55 @@
+package cast
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Error(t *testing.T) {
+	_, err := TryUInt8(256)
+	require.True(t, errors.Is(err, ErrOverflow))
+	require.False(t, errors.Is(err, ErrSyntax))
+
+	var castErr *Error
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, 256, castErr.Value)
+	require.Equal(t, "int", castErr.InputType)
+	require.Equal(t, "uint8", castErr.TargetType)
+	require.Equal(t, KindOverflow, castErr.Kind)
+	require.Equal(t, "unable to cast 256 of type int to uint8: desired type overflow", err.Error())
+
+	_, err = TryInt64("12a")
+	require.True(t, errors.Is(err, ErrSyntax))
+	var numErr *strconv.NumError
+	require.True(t, errors.As(err, &numErr))
+	require.Equal(t, "12a", numErr.Num)
+
+	_, err = TryInt8("1000")
+	require.True(t, errors.Is(err, ErrOverflow))
+	require.True(t, errors.As(err, &numErr))
+
+	_, err = TryFloat64(struct{}{})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, "struct {}", castErr.InputType)
+	require.Equal(t, "float64", castErr.TargetType)
+
+	_, err = TryBool("maybe")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryDuration("P1Y")
+	require.True(t, errors.Is(err, ErrSyntax))
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, "time.Duration", castErr.TargetType)
+
+	_, err = TryByteSize(-1)
+	require.True(t, errors.Is(err, ErrOverflow))
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, "byte size", castErr.TargetType)
+
+	_, err = TryUUID("not an uuid")
+	require.True(t, errors.Is(err, ErrSyntax))
+}
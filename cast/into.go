@@ -0,0 +1,171 @@
+package cast
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+)
+
+// TryInto converts value to the type out points to and stores the result, e.g.
+//
+// var id CameraID
+// err := cast.TryInto("cam-1", &id)
+//
+// Registered converters (see Register) are tried first, then encoding.TextUnmarshaler for string
+// values, then conversions of Try* functions by the underlying kind of the target type.
+// Slices, maps and pointers are converted element by element
+func TryInto(value interface{}, out interface{}, opts ...Option) error {
+	ptr := reflect.ValueOf(out)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return unsupportedError(out, "non-nil pointer")
+	}
+
+	converted, err := convertTo(value, ptr.Type().Elem(), newOptions(opts), opts)
+	if err != nil {
+		return err
+	}
+	ptr.Elem().Set(converted)
+	return nil
+}
+
+func convertTo(value interface{}, to reflect.Type, o *options, opts []Option) (reflect.Value, error) {
+	target := to.String()
+
+	if value != nil && reflect.TypeOf(value).AssignableTo(to) {
+		return reflect.ValueOf(value), nil
+	}
+	value = indirect(value)
+	if value == nil {
+		return reflect.Value{}, unsupportedError(value, target)
+	}
+
+	from := reflect.TypeOf(value)
+	if from.AssignableTo(to) {
+		return reflect.ValueOf(value), nil
+	}
+
+	if fn, ok := lookupConverter(from, to); ok {
+		converted, err := fn(value)
+		if err != nil {
+			return reflect.Value{}, convertError(value, target, err)
+		}
+		if converted == nil || !reflect.TypeOf(converted).AssignableTo(to) {
+			return reflect.Value{}, newError(value, target, KindUnsupportedType, nil)
+		}
+		return reflect.ValueOf(converted), nil
+	}
+
+	if reflect.PtrTo(to).Implements(textUnmarshalerType) {
+		if text, ok := textOf(value); ok {
+			ptr := reflect.New(to)
+			if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+				return reflect.Value{}, convertError(value, target, err)
+			}
+			return ptr.Elem(), nil
+		}
+	}
+
+	switch to {
+	case durationType:
+		d, err := TryDuration(value, opts...)
+		return reflect.ValueOf(d), err
+	case timeType:
+		t, err := TryDateTime(value, opts...)
+		return reflect.ValueOf(t), err
+	}
+
+	switch to.Kind() {
+	case reflect.Bool:
+		b, err := TryBool(value, opts...)
+		return reflect.ValueOf(b).Convert(to), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt(value, to.Bits(), target, o)
+		return reflect.ValueOf(n).Convert(to), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := toUint(value, to.Bits(), target, o)
+		return reflect.ValueOf(n).Convert(to), err
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(value, to.Bits(), target, o)
+		return reflect.ValueOf(f).Convert(to), err
+	case reflect.String:
+		s, err := TryString(value)
+		return reflect.ValueOf(s).Convert(to), err
+	case reflect.Ptr:
+		elem, err := convertTo(value, to.Elem(), o, opts)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(to.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Slice:
+		return convertToSlice(value, to, o, opts)
+	case reflect.Map:
+		return convertToMap(value, to, o, opts)
+	default:
+		return reflect.Value{}, unsupportedError(value, target)
+	}
+}
+
+func convertToSlice(value interface{}, to reflect.Type, o *options, opts []Option) (reflect.Value, error) {
+	target := to.String()
+
+	if to.Elem().Kind() == reflect.Uint8 {
+		if text, ok := textOf(value); ok {
+			return reflect.ValueOf(text).Convert(to), nil
+		}
+	}
+
+	items, err := sliceItems(value, target, o)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	slice := reflect.MakeSlice(to, len(items), len(items))
+	for i, item := range items {
+		elem, err := convertTo(item, to.Elem(), o, opts)
+		if err != nil {
+			return reflect.Value{}, elementError(value, target, strconv.Itoa(i), err)
+		}
+		slice.Index(i).Set(elem)
+	}
+	return slice, nil
+}
+
+func convertToMap(value interface{}, to reflect.Type, o *options, opts []Option) (reflect.Value, error) {
+	target := to.String()
+
+	items, err := mapItems(value, target, o)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	m := reflect.MakeMapWithSize(to, len(items))
+	for key, item := range items {
+		k, err := convertTo(key, to.Key(), o, opts)
+		if err != nil {
+			return reflect.Value{}, elementError(value, target, strconv.Quote(key), err)
+		}
+		v, err := convertTo(item, to.Elem(), o, opts)
+		if err != nil {
+			return reflect.Value{}, elementError(value, target, strconv.Quote(key), err)
+		}
+		m.SetMapIndex(k, v)
+	}
+	return m, nil
+}
+
+// textOf returns text of string-like values
+func textOf(value interface{}) ([]byte, bool) {
+	switch castedVal := value.(type) {
+	case string:
+		return []byte(castedVal), true
+	case []byte:
+		return castedVal, true
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+		return []byte(v.String()), true
+	}
+	return nil, false
+}
@@ -0,0 +1,110 @@
+package cast
+
+import (
+	"math/big"
+	"strings"
+)
+
+// NumberFormat describes how numeric strings are written, e.g. "1 234,56" is
+//
+// NumberFormat{DecimalSeparator: ',', ThousandsSeparators: " "}
+//
+// Base prefixes like "0x1F" are accepted for integers regardless of the format
+type NumberFormat struct {
+	// DecimalSeparator separates fractional part, '.' if not set
+	DecimalSeparator rune
+	// ThousandsSeparators lists runes grouping digits which are dropped before parsing.
+	// Space also matches no-break spaces commonly used for grouping
+	ThousandsSeparators string
+	// AllowUnderscores drops all underscores, not only ones placed by Go syntax rules like in "1_000"
+	AllowUnderscores bool
+	// AllowPercent accepts values like "12%" meaning 0.12
+	AllowPercent bool
+	// AllowExponent accepts scientific notation like "1e3" for integer types when the value is integral
+	AllowExponent bool
+}
+
+// WithNumberFormat sets the format of numeric strings for numeric Try* functions
+func WithNumberFormat(format NumberFormat) Option {
+	return func(o *options) {
+		o.numberFormat = &format
+	}
+}
+
+// numberText returns text of string value normalized according to the number format if it's set.
+// For integer targets exponent and percent forms are evaluated and must give an integer
+func (o *options) numberText(value interface{}, integer bool, target string) (string, error) {
+	var s string
+	switch castedVal := value.(type) {
+	case string:
+		s = castedVal
+	case []byte:
+		s = string(castedVal)
+	}
+
+	if o.numberFormat == nil {
+		return s, nil
+	}
+	return o.numberFormat.normalize(value, s, integer, target)
+}
+
+func (f *NumberFormat) normalize(value interface{}, s string, integer bool, target string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+
+	percent := f.AllowPercent && strings.HasSuffix(trimmed, "%")
+	if percent {
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "%"))
+	}
+
+	decimalSeparator := f.DecimalSeparator
+	if decimalSeparator == 0 {
+		decimalSeparator = '.'
+	}
+
+	var b strings.Builder
+	b.Grow(len(trimmed))
+	for _, r := range trimmed {
+		switch {
+		case f.isThousandsSeparator(r):
+		case f.AllowUnderscores && r == '_':
+		case r == decimalSeparator:
+			b.WriteByte('.')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	normalized := b.String()
+
+	unsigned := strings.TrimLeft(normalized, "+-")
+	if strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0X") {
+		return normalized, nil
+	}
+
+	exponent := integer && f.AllowExponent && strings.ContainsAny(normalized, "eE")
+	if !percent && !exponent {
+		return normalized, nil
+	}
+
+	r, ok := parseRat(normalized)
+	if !ok {
+		return "", syntaxError(value, target, "invalid number")
+	}
+	if percent {
+		r.Quo(r, big.NewRat(100, 1))
+	}
+	if integer {
+		if !r.IsInt() {
+			return "", newError(value, target, KindPrecisionLoss, nil)
+		}
+		return r.Num().String(), nil
+	}
+	return ratToDecimal(value, r)
+}
+
+func (f *NumberFormat) isThousandsSeparator(r rune) bool {
+	if strings.ContainsRune(f.ThousandsSeparators, r) {
+		return true
+	}
+	// no-break and narrow no-break spaces
+	return (r == '\u00a0' || r == '\u202f') && strings.ContainsRune(f.ThousandsSeparators, ' ')
+}
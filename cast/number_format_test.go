@@ -0,0 +1,169 @@
+package cast
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NumberFormat(t *testing.T) {
+	european := WithNumberFormat(NumberFormat{DecimalSeparator: ',', ThousandsSeparators: " ."})
+
+	f, err := TryFloat64("1 234,56", european)
+	require.NoError(t, err)
+	require.Equal(t, 1234.56, f)
+
+	f, err = TryFloat64("1.234.567,5", european)
+	require.NoError(t, err)
+	require.Equal(t, 1234567.5, f)
+
+	f, err = TryFloat64("1 234,5", european)
+	require.NoError(t, err)
+	require.Equal(t, 1234.5, f)
+
+	f32, err := TryFloat32("-0,25", european)
+	require.NoError(t, err)
+	require.Equal(t, float32(-0.25), f32)
+
+	n, err := TryInt64("1 234 567", european)
+	require.NoError(t, err)
+	require.Equal(t, int64(1234567), n)
+
+	_, err = TryFloat64("1 234,56")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	american := WithNumberFormat(NumberFormat{ThousandsSeparators: ","})
+	u, err := TryUInt32("4,294,967,295", american)
+	require.NoError(t, err)
+	require.Equal(t, uint32(4294967295), u)
+
+	_, err = TryUInt32("4,294,967,296", american)
+	require.True(t, errors.Is(err, ErrOverflow))
+}
+
+func Test_NumberFormat_Underscores(t *testing.T) {
+	underscores := WithNumberFormat(NumberFormat{AllowUnderscores: true})
+
+	n, err := TryInt64("1_000_000", underscores)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000000), n)
+
+	f, err := TryFloat64("1_000.5", underscores)
+	require.NoError(t, err)
+	require.Equal(t, 1000.5, f)
+
+	n, err = TryInt64("1__000_", underscores)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), n)
+
+	_, err = TryInt64("1__000_")
+	require.True(t, errors.Is(err, ErrSyntax))
+}
+
+func Test_NumberFormat_Percent(t *testing.T) {
+	percent := WithNumberFormat(NumberFormat{AllowPercent: true})
+
+	f, err := TryFloat64("12%", percent)
+	require.NoError(t, err)
+	require.Equal(t, 0.12, f)
+
+	f, err = TryFloat64("12.5 %", percent)
+	require.NoError(t, err)
+	require.Equal(t, 0.125, f)
+
+	n, err := TryInt64("1200%", percent)
+	require.NoError(t, err)
+	require.Equal(t, int64(12), n)
+
+	_, err = TryInt64("12%", percent)
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	s, err := TryDecimalString("0,5%", WithNumberFormat(NumberFormat{AllowPercent: true, DecimalSeparator: ','}))
+	require.NoError(t, err)
+	require.Equal(t, "0.005", s)
+
+	_, err = TryFloat64("12%")
+	require.True(t, errors.Is(err, ErrSyntax))
+}
+
+func Test_NumberFormat_Exponent(t *testing.T) {
+	exponent := WithNumberFormat(NumberFormat{AllowExponent: true})
+
+	n, err := TryInt64("1e3", exponent)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), n)
+
+	n, err = TryInt64("-1.5E2", exponent)
+	require.NoError(t, err)
+	require.Equal(t, int64(-150), n)
+
+	u, err := TryUInt8("2.55e2", exponent)
+	require.NoError(t, err)
+	require.Equal(t, uint8(255), u)
+
+	_, err = TryUInt8("2.56e2", exponent)
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryInt64("1.5e0", exponent)
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryInt64("1e3")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	n, err = TryInt64("0x1F", exponent)
+	require.NoError(t, err)
+	require.Equal(t, int64(31), n)
+
+	n, err = TryInt64("0x1E3", exponent)
+	require.NoError(t, err)
+	require.Equal(t, int64(0x1e3), n)
+}
+
+func Test_NumberFormat_Big(t *testing.T) {
+	format := WithNumberFormat(NumberFormat{DecimalSeparator: ',', ThousandsSeparators: " "})
+
+	i, err := TryBigInt("123 456 789 012 345 678 901 234 567 890", format)
+	require.NoError(t, err)
+	require.Equal(t, "123456789012345678901234567890", i.String())
+
+	s, err := TryDecimalString("1 234,50", format)
+	require.NoError(t, err)
+	require.Equal(t, "1234.5", s)
+
+	bf, err := TryBigFloat("1 234,5", format)
+	require.NoError(t, err)
+	require.Equal(t, "1234.5", bf.Text('f', -1))
+
+	ns, err := TryInt64Slice([]string{"1 000", "2 000"}, format)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1000, 2000}, ns)
+}
+
+func Test_NumberFormat_DurationAndByteSize(t *testing.T) {
+	format := WithNumberFormat(NumberFormat{DecimalSeparator: ',', ThousandsSeparators: " "})
+
+	d, err := TryDuration("1,5h", format)
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, d)
+
+	d, err = TryDuration("1 800", format)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, d)
+
+	d, err = TryDuration("PT1,5S", format)
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, d)
+
+	size, err := TryByteSize("1 536 KiB", format)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1536<<10), size)
+
+	size, err = TryByteSize("1,5KiB", format)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1536), size)
+
+	_, err = TryByteSize("1,5KiB")
+	require.Error(t, err)
+}
@@ -0,0 +1,318 @@
+package cast
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// toInt converts value to a signed integer of bitSize bits
+func toInt(value interface{}, bitSize int, target string, o *options) (int64, error) {
+	min := int64(-1) << uint(bitSize-1)
+	max := -(min + 1)
+
+	switch castedVal := value.(type) {
+	case int:
+		return checkInt(value, int64(castedVal), min, max, target)
+	case int64:
+		return checkInt(value, castedVal, min, max, target)
+	case int32:
+		return checkInt(value, int64(castedVal), min, max, target)
+	case int16:
+		return checkInt(value, int64(castedVal), min, max, target)
+	case int8:
+		return checkInt(value, int64(castedVal), min, max, target)
+	case uint:
+		return checkUintToInt(value, uint64(castedVal), max, target)
+	case uint64:
+		return checkUintToInt(value, castedVal, max, target)
+	case uint32:
+		return checkUintToInt(value, uint64(castedVal), max, target)
+	case uint16:
+		return checkUintToInt(value, uint64(castedVal), max, target)
+	case uint8:
+		return checkUintToInt(value, uint64(castedVal), max, target)
+	case float64:
+		return floatToInt(value, castedVal, min, target, o)
+	case float32:
+		return floatToInt(value, float64(castedVal), min, target, o)
+	case string:
+		text, err := o.numberText(value, true, target)
+		if err != nil {
+			return 0, err
+		}
+		v, err := strconv.ParseInt(text, 0, bitSize)
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	case big.Int:
+		if !castedVal.IsInt64() {
+			return 0, overflowError(value, target)
+		}
+		return checkInt(value, castedVal.Int64(), min, max, target)
+	case json.Number:
+		v, err := strconv.ParseInt(string(castedVal), 10, bitSize)
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrSyntax {
+			return jsonNumberToInt(value, castedVal, min, target, o)
+		}
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	default:
+		converted, ok, err := fallback(value, target)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, unsupportedError(value, target)
+		}
+		return toInt(converted, bitSize, target, o)
+	}
+}
+
+// toUint converts value to an unsigned integer of bitSize bits
+func toUint(value interface{}, bitSize int, target string, o *options) (uint64, error) {
+	max := uint64(math.MaxUint64) >> uint(64-bitSize)
+
+	switch castedVal := value.(type) {
+	case int:
+		return checkIntToUint(value, int64(castedVal), max, target)
+	case int64:
+		return checkIntToUint(value, castedVal, max, target)
+	case int32:
+		return checkIntToUint(value, int64(castedVal), max, target)
+	case int16:
+		return checkIntToUint(value, int64(castedVal), max, target)
+	case int8:
+		return checkIntToUint(value, int64(castedVal), max, target)
+	case uint:
+		return checkUint(value, uint64(castedVal), max, target)
+	case uint64:
+		return checkUint(value, castedVal, max, target)
+	case uint32:
+		return checkUint(value, uint64(castedVal), max, target)
+	case uint16:
+		return checkUint(value, uint64(castedVal), max, target)
+	case uint8:
+		return checkUint(value, uint64(castedVal), max, target)
+	case float64:
+		return floatToUint(value, castedVal, max, target, o)
+	case float32:
+		return floatToUint(value, float64(castedVal), max, target, o)
+	case string:
+		text, err := o.numberText(value, true, target)
+		if err != nil {
+			return 0, err
+		}
+		v, err := strconv.ParseUint(text, 0, bitSize)
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	case big.Int:
+		if !castedVal.IsUint64() {
+			return 0, overflowError(value, target)
+		}
+		return checkUint(value, castedVal.Uint64(), max, target)
+	case json.Number:
+		v, err := strconv.ParseUint(string(castedVal), 10, bitSize)
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrSyntax {
+			return jsonNumberToUint(value, castedVal, max, target, o)
+		}
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	default:
+		converted, ok, err := fallback(value, target)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, unsupportedError(value, target)
+		}
+		return toUint(converted, bitSize, target, o)
+	}
+}
+
+// toFloat converts value to a float of bitSize bits
+func toFloat(value interface{}, bitSize int, target string, o *options) (float64, error) {
+	switch castedVal := value.(type) {
+	case float64:
+		if bitSize == 64 {
+			return castedVal, nil
+		}
+		if castedVal < -math.MaxFloat32 || castedVal > math.MaxFloat32 {
+			return 0, overflowError(value, target)
+		}
+		if o.policy == PolicyStrict && float64(float32(castedVal)) != castedVal && !math.IsNaN(castedVal) {
+			return 0, newError(value, target, KindPrecisionLoss, nil)
+		}
+		return castedVal, nil
+	case float32:
+		return float64(castedVal), nil
+	case int:
+		return intToFloat(value, int64(castedVal), bitSize, target, o)
+	case int64:
+		return intToFloat(value, castedVal, bitSize, target, o)
+	case int32:
+		return intToFloat(value, int64(castedVal), bitSize, target, o)
+	case int16:
+		return intToFloat(value, int64(castedVal), bitSize, target, o)
+	case int8:
+		return intToFloat(value, int64(castedVal), bitSize, target, o)
+	case uint:
+		return uintToFloat(value, uint64(castedVal), bitSize, target, o)
+	case uint64:
+		return uintToFloat(value, castedVal, bitSize, target, o)
+	case uint32:
+		return uintToFloat(value, uint64(castedVal), bitSize, target, o)
+	case uint16:
+		return uintToFloat(value, uint64(castedVal), bitSize, target, o)
+	case uint8:
+		return uintToFloat(value, uint64(castedVal), bitSize, target, o)
+	case string:
+		text, err := o.numberText(value, false, target)
+		if err != nil {
+			return 0, err
+		}
+		v, err := strconv.ParseFloat(text, bitSize)
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	case big.Int:
+		return bigIntToFloat(value, &castedVal, bitSize, target, o)
+	case json.Number:
+		v, err := strconv.ParseFloat(string(castedVal), bitSize)
+		if err != nil {
+			return 0, parseError(value, target, err)
+		}
+		return v, nil
+	default:
+		converted, ok, err := fallback(value, target)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, unsupportedError(value, target)
+		}
+		return toFloat(converted, bitSize, target, o)
+	}
+}
+
+func checkInt(value interface{}, v, min, max int64, target string) (int64, error) {
+	if v < min || v > max {
+		return 0, overflowError(value, target)
+	}
+	return v, nil
+}
+
+func checkUint(value interface{}, v, max uint64, target string) (uint64, error) {
+	if v > max {
+		return 0, overflowError(value, target)
+	}
+	return v, nil
+}
+
+func checkUintToInt(value interface{}, v uint64, max int64, target string) (int64, error) {
+	if v > uint64(max) {
+		return 0, overflowError(value, target)
+	}
+	return int64(v), nil
+}
+
+func checkIntToUint(value interface{}, v int64, max uint64, target string) (uint64, error) {
+	if v < 0 || uint64(v) > max {
+		return 0, overflowError(value, target)
+	}
+	return uint64(v), nil
+}
+
+func floatToInt(value interface{}, f float64, min int64, target string, o *options) (int64, error) {
+	f, err := o.policy.integral(value, f, target)
+	if err != nil {
+		return 0, err
+	}
+	// -min is the first integer out of range, it's exactly representable as float unlike max
+	if f < float64(min) || f >= -float64(min) {
+		return 0, overflowError(value, target)
+	}
+	return int64(f), nil
+}
+
+func floatToUint(value interface{}, f float64, max uint64, target string, o *options) (uint64, error) {
+	f, err := o.policy.integral(value, f, target)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 || f >= float64(max)+1 {
+		return 0, overflowError(value, target)
+	}
+	return uint64(f), nil
+}
+
+// jsonNumberToInt converts JSON numbers with fraction or exponent like floats
+func jsonNumberToInt(value interface{}, n json.Number, min int64, target string, o *options) (int64, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return 0, parseError(value, target, err)
+	}
+	return floatToInt(value, f, min, target, o)
+}
+
+func jsonNumberToUint(value interface{}, n json.Number, max uint64, target string, o *options) (uint64, error) {
+	f, err := n.Float64()
+	if err != nil {
+		return 0, parseError(value, target, err)
+	}
+	return floatToUint(value, f, max, target, o)
+}
+
+func intToFloat(value interface{}, v int64, bitSize int, target string, o *options) (float64, error) {
+	f := roundFloat(float64(v), bitSize)
+	if o.policy == PolicyStrict && (f >= math.MaxInt64 || int64(f) != v) {
+		return 0, newError(value, target, KindPrecisionLoss, nil)
+	}
+	return f, nil
+}
+
+func uintToFloat(value interface{}, v uint64, bitSize int, target string, o *options) (float64, error) {
+	f := roundFloat(float64(v), bitSize)
+	if o.policy == PolicyStrict && (f >= math.MaxUint64 || uint64(f) != v) {
+		return 0, newError(value, target, KindPrecisionLoss, nil)
+	}
+	return f, nil
+}
+
+func bigIntToFloat(value interface{}, v *big.Int, bitSize int, target string, o *options) (float64, error) {
+	var (
+		f   float64
+		acc big.Accuracy
+	)
+	if bitSize == 32 {
+		var f32 float32
+		f32, acc = new(big.Float).SetInt(v).Float32()
+		f = float64(f32)
+	} else {
+		f, acc = new(big.Float).SetInt(v).Float64()
+	}
+
+	if math.IsInf(f, 0) {
+		return 0, overflowError(value, target)
+	}
+	if o.policy == PolicyStrict && acc != big.Exact {
+		return 0, newError(value, target, KindPrecisionLoss, nil)
+	}
+	return f, nil
+}
+
+func roundFloat(f float64, bitSize int) float64 {
+	if bitSize == 32 {
+		return float64(float32(f))
+	}
+	return f
+}
@@ -0,0 +1,115 @@
+package cast
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Option tunes a single conversion, e.g. TryBool(v, WithBoolStrings(truthy, falsy))
+type Option func(*options)
+
+type options struct {
+	truthy []string
+	falsy  []string
+
+	durationUnit time.Duration
+
+	location  *time.Location
+	layouts   []string
+	epochUnit time.Duration
+
+	policy Policy
+
+	separator string
+
+	uuidVersions []uuid.Version
+
+	numberFormat *NumberFormat
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		truthy: defaultTruthy,
+		falsy:  defaultFalsy,
+
+		durationUnit: time.Second,
+
+		location: time.UTC,
+
+		separator: ",",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithBoolStrings replaces the vocabularies used by TryBool to recognize true and false strings.
+// Comparison is case-insensitive, nil keeps the default vocabulary
+func WithBoolStrings(truthy, falsy []string) Option {
+	return func(o *options) {
+		if truthy != nil {
+			o.truthy = truthy
+		}
+		if falsy != nil {
+			o.falsy = falsy
+		}
+	}
+}
+
+// WithDurationUnit sets the unit TryDuration applies to plain numbers, time.Second by default
+func WithDurationUnit(unit time.Duration) Option {
+	return func(o *options) {
+		if unit > 0 {
+			o.durationUnit = unit
+		}
+	}
+}
+
+// WithLocation sets the location TryDate and TryDateTime use for inputs without zone information, UTC by default
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		if loc != nil {
+			o.location = loc
+		}
+	}
+}
+
+// WithLayouts adds time layouts tried by TryDate and TryDateTime before the registered and built-in ones
+func WithLayouts(layouts ...string) Option {
+	return func(o *options) {
+		o.layouts = append(o.layouts, layouts...)
+	}
+}
+
+// WithEpochUnit sets the unit of numeric timestamps (time.Second, time.Millisecond,
+// time.Microsecond or time.Nanosecond). By default the unit is guessed from the magnitude
+func WithEpochUnit(unit time.Duration) Option {
+	return func(o *options) {
+		o.epochUnit = unit
+	}
+}
+
+// WithPolicy sets the policy for numeric conversions, PolicyTruncate by default
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithSeparator sets the separator of list items in strings converted to slices and maps, "," by default
+func WithSeparator(sep string) Option {
+	return func(o *options) {
+		if sep != "" {
+			o.separator = sep
+		}
+	}
+}
+
+// WithUUIDVersion makes TryUUID and TryUUIDValue reject UUIDs of versions other than listed
+func WithUUIDVersion(versions ...uuid.Version) Option {
+	return func(o *options) {
+		o.uuidVersions = append(o.uuidVersions, versions...)
+	}
+}
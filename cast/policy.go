@@ -0,0 +1,35 @@
+package cast
+
+import "math"
+
+// Policy defines how numeric conversions deal with values the target type can't represent exactly
+type Policy int
+
+const (
+	// PolicyTruncate drops fractional part of floats converted to integers, default
+	PolicyTruncate Policy = iota
+	// PolicyRound rounds floats converted to integers half to even
+	PolicyRound
+	// PolicyStrict rejects floats with fractional part converted to integers and any
+	// conversion which can't be represented exactly by the target type
+	PolicyStrict
+)
+
+// integral prepares float f for conversion to an integer type according to the policy
+func (p Policy) integral(value interface{}, f float64, target string) (float64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, overflowError(value, target)
+	}
+
+	switch p {
+	case PolicyStrict:
+		if f != math.Trunc(f) {
+			return 0, newError(value, target, KindPrecisionLoss, nil)
+		}
+		return f, nil
+	case PolicyRound:
+		return math.RoundToEven(f), nil
+	default:
+		return math.Trunc(f), nil
+	}
+}
@@ -0,0 +1,109 @@
+package cast
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Policy_FloatToInt(t *testing.T) {
+	truncated, err := TryInt64(3.9)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), truncated)
+
+	truncated, err = TryInt64(-3.9, WithPolicy(PolicyTruncate))
+	require.NoError(t, err)
+	require.Equal(t, int64(-3), truncated)
+
+	rounded, err := TryInt64(3.9, WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, int64(4), rounded)
+
+	roundedHalf, err := TryUInt8(2.5, WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, uint8(2), roundedHalf)
+
+	roundedHalf, err = TryUInt8(float32(3.5), WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, uint8(4), roundedHalf)
+
+	_, err = TryUInt8(255.5, WithPolicy(PolicyRound))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryInt64(3.9, WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryInt32(float32(1.5), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	exact, err := TryInt16(float32(-300), WithPolicy(PolicyStrict))
+	require.NoError(t, err)
+	require.Equal(t, int16(-300), exact)
+
+	_, err = TryInt64(float32(math.MaxFloat32))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryInt64(math.NaN())
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryUInt64(math.Inf(1))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = TryInt64(float64(1 << 63))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	minInt64, err := TryInt64(float64(math.MinInt64))
+	require.NoError(t, err)
+	require.Equal(t, int64(math.MinInt64), minInt64)
+}
+
+func Test_Policy_ToFloat(t *testing.T) {
+	f32, err := TryFloat32(int64(1<<24 + 1))
+	require.NoError(t, err)
+	require.Equal(t, float32(1<<24), f32)
+
+	_, err = TryFloat32(int64(1<<24+1), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	f32, err = TryFloat32(int64(1<<24), WithPolicy(PolicyStrict))
+	require.NoError(t, err)
+	require.Equal(t, float32(1<<24), f32)
+
+	_, err = TryFloat32(0.1, WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	f32, err = TryFloat32(0.5, WithPolicy(PolicyStrict))
+	require.NoError(t, err)
+	require.Equal(t, float32(0.5), f32)
+
+	_, err = TryFloat64(int64(1<<53+1), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryFloat64(uint64(math.MaxUint64), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	_, err = TryFloat64(int64(math.MaxInt64), WithPolicy(PolicyStrict))
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	f64, err := TryFloat64(uint64(1<<53), WithPolicy(PolicyStrict))
+	require.NoError(t, err)
+	require.Equal(t, float64(1<<53), f64)
+}
+
+func Test_Caster(t *testing.T) {
+	strict := NewCaster(WithPolicy(PolicyStrict))
+
+	_, err := strict.TryInt64(3.9)
+	require.True(t, errors.Is(err, ErrPrecisionLoss))
+
+	rounded, err := strict.TryInt64(3.9, WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, int64(4), rounded)
+
+	var nilCaster *Caster
+	truncated, err := nilCaster.TryInt64(3.9)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), truncated)
+}
@@ -0,0 +1,132 @@
+package cast
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ConverterFunc converts value to the type it's registered for
+type ConverterFunc func(value interface{}) (interface{}, error)
+
+type converterKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+var (
+	convertersMu sync.RWMutex
+	converters   = make(map[converterKey]ConverterFunc)
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// targetTypes maps target names used by Try* functions to their types for converters lookup
+var targetTypes = map[string]reflect.Type{
+	"bool":         reflect.TypeOf(false),
+	"string":       reflect.TypeOf(""),
+	"int8":         reflect.TypeOf(int8(0)),
+	"int16":        reflect.TypeOf(int16(0)),
+	"int32":        reflect.TypeOf(int32(0)),
+	"int64":        reflect.TypeOf(int64(0)),
+	"uint8":        reflect.TypeOf(uint8(0)),
+	"uint16":       reflect.TypeOf(uint16(0)),
+	"uint32":       reflect.TypeOf(uint32(0)),
+	"uint64":       reflect.TypeOf(uint64(0)),
+	"float32":      reflect.TypeOf(float32(0)),
+	"float64":      reflect.TypeOf(float64(0)),
+	durationTarget: durationType,
+	timeTarget:     timeType,
+}
+
+// Register adds converter of values of the type of from to the type of to, both are sample values, e.g.
+//
+// cast.Register(Money{}, float64(0), func(v interface{}) (interface{}, error) { return v.(Money).Float64(), nil })
+//
+// Converters are used by Try* functions for types they don't know and by TryInto
+func Register(from, to interface{}, fn ConverterFunc) {
+	if from == nil || to == nil || fn == nil {
+		panic("cast: Register with nil argument")
+	}
+
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	converters[converterKey{from: reflect.TypeOf(from), to: reflect.TypeOf(to)}] = fn
+}
+
+func lookupConverter(from, to reflect.Type) (ConverterFunc, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+
+	fn, ok := converters[converterKey{from: from, to: to}]
+	return fn, ok
+}
+
+// fallback converts value of a type unknown to a Try* function to a type it knows: with a registered
+// converter, with encoding.TextMarshaler or fmt.Stringer for string target, or by the underlying kind
+// of a named type like `type CameraID string`. ok is false if none of these applies
+func fallback(value interface{}, target string) (converted interface{}, ok bool, err error) {
+	if value == nil {
+		return nil, false, nil
+	}
+	from := reflect.TypeOf(value)
+
+	if to, known := targetTypes[target]; known {
+		if fn, registered := lookupConverter(from, to); registered {
+			converted, err = fn(value)
+			if err != nil {
+				return nil, true, convertError(value, target, err)
+			}
+			return converted, converted != nil && reflect.TypeOf(converted) != from, nil
+		}
+	}
+
+	if target == "string" {
+		switch castedVal := value.(type) {
+		case encoding.TextMarshaler:
+			text, err := castedVal.MarshalText()
+			if err != nil {
+				return nil, true, convertError(value, target, err)
+			}
+			return string(text), true, nil
+		case fmt.Stringer:
+			return castedVal.String(), true, nil
+		case error:
+			return castedVal.Error(), true, nil
+		}
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		converted = v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		converted = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		converted = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		converted = v.Float()
+	case reflect.String:
+		converted = v.String()
+	default:
+		return nil, false, nil
+	}
+	return converted, reflect.TypeOf(converted) != from, nil
+}
+
+// convertError wraps error returned by a converter, kind of *Error is kept
+func convertError(value interface{}, target string, err error) error {
+	kind := KindSyntax
+	if castErr, ok := err.(*Error); ok {
+		kind = castErr.Kind
+	}
+	return newError(value, target, kind, err)
+}
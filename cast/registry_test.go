@@ -0,0 +1,242 @@
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCameraID string
+
+type testCounter int32
+
+type testSeverity int
+
+const (
+	testSeverityLow testSeverity = iota + 1
+	testSeverityHigh
+)
+
+func (s testSeverity) MarshalText() ([]byte, error) {
+	switch s {
+	case testSeverityLow:
+		return []byte("low"), nil
+	case testSeverityHigh:
+		return []byte("high"), nil
+	default:
+		return nil, fmt.Errorf("unknown severity %d", int(s))
+	}
+}
+
+func (s *testSeverity) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*s = testSeverityLow
+	case "high":
+		*s = testSeverityHigh
+	default:
+		return fmt.Errorf("unknown severity %q", text)
+	}
+	return nil
+}
+
+type testMoney struct {
+	cents int64
+}
+
+type testVersion struct {
+	major, minor int
+}
+
+func (v testVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+func Test_UnderlyingKind(t *testing.T) {
+	s, err := TryString(testCameraID("cam-1"))
+	require.NoError(t, err)
+	require.Equal(t, "cam-1", s)
+
+	n, err := TryInt64(testCounter(42))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), n)
+
+	u8, err := TryUInt8(testCounter(42))
+	require.NoError(t, err)
+	require.Equal(t, uint8(42), u8)
+
+	_, err = TryUInt8(testCounter(-1))
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	f, err := TryFloat64(testCounter(42))
+	require.NoError(t, err)
+	require.Equal(t, float64(42), f)
+
+	n, err = TryInt64(testCameraID("12"))
+	require.NoError(t, err)
+	require.Equal(t, int64(12), n)
+
+	b, err := TryBool(testCounter(1))
+	require.NoError(t, err)
+	require.True(t, b)
+
+	d, err := TryDuration(testCounter(2))
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, d)
+
+	counter := testCounter(7)
+	n, err = TryInt64(&counter)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+
+	s, err = TryString(true)
+	require.NoError(t, err)
+	require.Equal(t, "true", s)
+}
+
+func Test_TextMarshaler(t *testing.T) {
+	s, err := TryString(testSeverityHigh)
+	require.NoError(t, err)
+	require.Equal(t, "high", s)
+
+	_, err = TryString(testSeverity(5))
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	n, err := TryInt64(testSeverityHigh)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	s, err = TryString(testVersion{major: 1, minor: 2})
+	require.NoError(t, err)
+	require.Equal(t, "1.2", s)
+
+	s, err = TryString(errors.New("boom"))
+	require.NoError(t, err)
+	require.Equal(t, "boom", s)
+}
+
+func Test_Register(t *testing.T) {
+	_, err := TryFloat64(testMoney{cents: 150})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+
+	defer unregister(testMoney{}, float64(0))
+	defer unregister("", testMoney{})
+	defer unregister(testMoney{}, "")
+
+	Register(testMoney{}, float64(0), func(v interface{}) (interface{}, error) {
+		return float64(v.(testMoney).cents) / 100, nil
+	})
+	Register(testMoney{}, "", func(v interface{}) (interface{}, error) {
+		return fmt.Sprintf("%d.%02d", v.(testMoney).cents/100, v.(testMoney).cents%100), nil
+	})
+	Register("", testMoney{}, func(v interface{}) (interface{}, error) {
+		var units, cents int64
+		if _, err := fmt.Sscanf(v.(string), "%d.%d", &units, &cents); err != nil {
+			return nil, err
+		}
+		return testMoney{cents: units*100 + cents}, nil
+	})
+
+	f, err := TryFloat64(testMoney{cents: 150})
+	require.NoError(t, err)
+	require.Equal(t, 1.5, f)
+
+	s, err := TryString(&testMoney{cents: 1005})
+	require.NoError(t, err)
+	require.Equal(t, "10.05", s)
+
+	_, err = TryInt64(testMoney{cents: 150})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+
+	var m testMoney
+	require.NoError(t, TryInto("12.34", &m))
+	require.Equal(t, testMoney{cents: 1234}, m)
+
+	err = TryInto("twelve", &m)
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	require.Panics(t, func() { Register(nil, "", nil) })
+}
+
+func Test_TryInto(t *testing.T) {
+	var id testCameraID
+	require.NoError(t, TryInto("cam-1", &id))
+	require.Equal(t, testCameraID("cam-1"), id)
+
+	require.NoError(t, TryInto(15, &id))
+	require.Equal(t, testCameraID("15"), id)
+
+	var severity testSeverity
+	require.NoError(t, TryInto("HIGH", &severity))
+	require.Equal(t, testSeverityHigh, severity)
+
+	require.NoError(t, TryInto(1, &severity))
+	require.Equal(t, testSeverityLow, severity)
+
+	require.Error(t, TryInto("medium", &severity))
+
+	var counter testCounter
+	require.NoError(t, TryInto("12", &counter))
+	require.Equal(t, testCounter(12), counter)
+
+	require.NoError(t, TryInto(2.6, &counter, WithPolicy(PolicyRound)))
+	require.Equal(t, testCounter(3), counter)
+
+	err := TryInto(int64(1<<40), &counter)
+	require.True(t, errors.Is(err, ErrOverflow))
+
+	var d time.Duration
+	require.NoError(t, TryInto("PT1M", &d))
+	require.Equal(t, time.Minute, d)
+
+	var ts time.Time
+	require.NoError(t, TryInto("2019-03-27T11:10:14Z", &ts))
+	require.Equal(t, time.Date(2019, 3, 27, 11, 10, 14, 0, time.UTC), ts)
+
+	var ids []testCameraID
+	require.NoError(t, TryInto("a,b", &ids))
+	require.Equal(t, []testCameraID{"a", "b"}, ids)
+
+	var severities map[string]testSeverity
+	require.NoError(t, TryInto(`{"x": "low", "y": "high"}`, &severities))
+	require.Equal(t, map[string]testSeverity{"x": testSeverityLow, "y": testSeverityHigh}, severities)
+
+	var counts map[testCameraID]uint8
+	err = TryInto("a=1,b=256", &counts)
+	require.True(t, errors.Is(err, ErrOverflow))
+	var castErr *Error
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, `"b"`, castErr.Key)
+
+	var ptr *int
+	require.NoError(t, TryInto("5", &ptr))
+	require.Equal(t, 5, *ptr)
+
+	var raw []byte
+	require.NoError(t, TryInto("abc", &raw))
+	require.Equal(t, []byte("abc"), raw)
+
+	var any interface{}
+	require.NoError(t, TryInto("abc", &any))
+	require.Equal(t, "abc", any)
+
+	require.Error(t, TryInto("abc", id))
+	require.Error(t, TryInto(nil, &id))
+	require.Error(t, TryInto("abc", &struct{}{}))
+}
+
+func unregister(from, to interface{}) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	for key := range converters {
+		if key.from == reflect.TypeOf(from) && key.to == reflect.TypeOf(to) {
+			delete(converters, key)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package cast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TryStringSlice casts value to []string. Accepted values are slices and arrays of any
+// element type, JSON array strings and strings with items separated by comma (see WithSeparator).
+// Strings which aren't valid JSON arrays are split even if they start with '[', e.g. "[::1]:80,[::2]:81"
+func TryStringSlice(value interface{}, opts ...Option) ([]string, error) {
+	const target = "[]string"
+
+	items, err := sliceItems(value, target, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make([]string, len(items))
+	for i, item := range items {
+		if casted[i], err = TryString(item); err != nil {
+			return nil, elementError(value, target, strconv.Itoa(i), err)
+		}
+	}
+	return casted, nil
+}
+
+// TryInt64Slice casts value to []int64, accepted values are the same as in TryStringSlice
+func TryInt64Slice(value interface{}, opts ...Option) ([]int64, error) {
+	const target = "[]int64"
+
+	items, err := sliceItems(value, target, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make([]int64, len(items))
+	for i, item := range items {
+		if casted[i], err = TryInt64(item, opts...); err != nil {
+			return nil, elementError(value, target, strconv.Itoa(i), err)
+		}
+	}
+	return casted, nil
+}
+
+// TryFloat64Slice casts value to []float64, accepted values are the same as in TryStringSlice
+func TryFloat64Slice(value interface{}, opts ...Option) ([]float64, error) {
+	const target = "[]float64"
+
+	items, err := sliceItems(value, target, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make([]float64, len(items))
+	for i, item := range items {
+		if casted[i], err = TryFloat64(item, opts...); err != nil {
+			return nil, elementError(value, target, strconv.Itoa(i), err)
+		}
+	}
+	return casted, nil
+}
+
+// TryStringMap casts value to map[string]interface{}. Accepted values are maps with keys
+// convertible to string, JSON object strings and strings with key=value pairs separated by comma
+func TryStringMap(value interface{}, opts ...Option) (map[string]interface{}, error) {
+	return mapItems(value, "map[string]interface {}", newOptions(opts))
+}
+
+// TryStringMapString casts value to map[string]string, accepted values are the same as in TryStringMap
+func TryStringMapString(value interface{}, opts ...Option) (map[string]string, error) {
+	const target = "map[string]string"
+
+	items, err := mapItems(value, target, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	casted := make(map[string]string, len(items))
+	for key, item := range items {
+		if casted[key], err = TryString(item); err != nil {
+			return nil, elementError(value, target, strconv.Quote(key), err)
+		}
+	}
+	return casted, nil
+}
+
+func sliceItems(value interface{}, target string, o *options) ([]interface{}, error) {
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case nil:
+		return nil, unsupportedError(value, target)
+	case []interface{}:
+		return castedVal, nil
+	case string:
+		return splitItems(value, castedVal, target, o)
+	case []byte:
+		return splitItems(value, string(castedVal), target, o)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, unsupportedError(value, target)
+	}
+
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, nil
+}
+
+func splitItems(value interface{}, s string, target string, o *options) ([]interface{}, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return []interface{}{}, nil
+	}
+
+	// strings like "[::1]:80,[::2]:81" start with '[' too, so only a valid JSON array is decoded
+	if strings.HasPrefix(trimmed, "[") {
+		var items []interface{}
+		if err := decodeJSON(trimmed, &items); err == nil {
+			return items, nil
+		}
+	}
+
+	parts := strings.Split(trimmed, o.separator)
+	items := make([]interface{}, len(parts))
+	for i, part := range parts {
+		items[i] = strings.TrimSpace(part)
+	}
+	return items, nil
+}
+
+func mapItems(value interface{}, target string, o *options) (map[string]interface{}, error) {
+	value = indirect(value)
+
+	switch castedVal := value.(type) {
+	case nil:
+		return nil, unsupportedError(value, target)
+	case map[string]interface{}:
+		return castedVal, nil
+	case string:
+		return splitPairs(value, castedVal, target, o)
+	case []byte:
+		return splitPairs(value, string(castedVal), target, o)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		return nil, unsupportedError(value, target)
+	}
+
+	items := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := TryString(iter.Key().Interface())
+		if err != nil {
+			return nil, elementError(value, target, fmt.Sprint(iter.Key().Interface()), err)
+		}
+		items[key] = iter.Value().Interface()
+	}
+	return items, nil
+}
+
+func splitPairs(value interface{}, s string, target string, o *options) (map[string]interface{}, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return map[string]interface{}{}, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		var items map[string]interface{}
+		if err := decodeJSON(trimmed, &items); err != nil {
+			return nil, newError(value, target, KindSyntax, err)
+		}
+		return items, nil
+	}
+
+	items := make(map[string]interface{})
+	for i, pair := range strings.Split(trimmed, o.separator) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, elementError(value, target, strconv.Itoa(i), syntaxError(pair, "key=value pair", "invalid pair"))
+		}
+		items[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return items, nil
+}
+
+// decodeJSON decodes s keeping numbers as json.Number, so big integers don't lose precision
+func decodeJSON(s string, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
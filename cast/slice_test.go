@@ -0,0 +1,140 @@
+package cast
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryStringSlice(t *testing.T) {
+	testTryStringSlice(t, []string{"a", "b", "c"},
+		[]interface{}{"a", "b", "c"},
+		[]string{"a", "b", "c"},
+		[3]string{"a", "b", "c"},
+		"a,b,c",
+		" a , b ,c ",
+		`["a", "b", "c"]`,
+		[]byte("a,b,c"),
+	)
+	testTryStringSlice(t, []string{"1", "2.5", "3"}, []interface{}{1, 2.5, "3"}, `[1, 2.5, "3"]`)
+	testTryStringSlice(t, []string{}, "", " ", "[]", []int{})
+
+	testTryStringSlice(t, []string{"[::1]:80", "[::2]:81"}, "[::1]:80,[::2]:81", `["[::1]:80", "[::2]:81"]`)
+	testTryStringSlice(t, []string{"[1", "2]x"}, "[1,2]x")
+
+	casted, err := TryStringSlice("a;b", WithSeparator(";"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, casted)
+
+	s := []string{"x"}
+	casted, err = TryStringSlice(&s)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x"}, casted)
+
+	testTryStringSlice(t, []string{`["a"`, ""}, `["a",`)
+	testTryStringSlice(t, []string{`["a"] x`}, `["a"] x`)
+
+	testTryStringSliceErr(t, nil, 1, struct{}{}, []interface{}{"a", struct{}{}})
+}
+
+func Test_TryInt64Slice(t *testing.T) {
+	casted, err := TryInt64Slice([]interface{}{1, int8(2), "3", 4.0})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3, 4}, casted)
+
+	casted, err = TryInt64Slice("1, 2, 0x10")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 16}, casted)
+
+	casted, err = TryInt64Slice(`[9007199254740993, 2.9]`)
+	require.NoError(t, err)
+	require.Equal(t, []int64{9007199254740993, 2}, casted)
+
+	casted, err = TryInt64Slice([]float32{1.5}, WithPolicy(PolicyRound))
+	require.NoError(t, err)
+	require.Equal(t, []int64{2}, casted)
+
+	_, err = TryInt64Slice([]interface{}{1, 2, "x"})
+	require.True(t, errors.Is(err, ErrSyntax))
+	var castErr *Error
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, "2", castErr.Key)
+	require.Equal(t, "[]int64", castErr.TargetType)
+	require.Contains(t, err.Error(), "unable to cast element 2 of []interface {} to []int64")
+
+	_, err = TryInt64Slice([]uint64{1, 1 << 63})
+	require.True(t, errors.Is(err, ErrOverflow))
+}
+
+func Test_TryFloat64Slice(t *testing.T) {
+	casted, err := TryFloat64Slice([]interface{}{1, "2.5", float32(0.5)})
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 2.5, 0.5}, casted)
+
+	casted, err = TryFloat64Slice("[1e3, -0.25]")
+	require.NoError(t, err)
+	require.Equal(t, []float64{1000, -0.25}, casted)
+
+	_, err = TryFloat64Slice("1,a")
+	require.True(t, errors.Is(err, ErrSyntax))
+}
+
+func Test_TryStringMap(t *testing.T) {
+	casted, err := TryStringMap(`{"a": 1, "b": {"c": "d"}}`)
+	require.NoError(t, err)
+	require.Len(t, casted, 2)
+	require.Equal(t, map[string]interface{}{"c": "d"}, casted["b"])
+
+	casted, err = TryStringMap(map[int]interface{}{1: "a"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"1": "a"}, casted)
+
+	_, err = TryStringMap([]string{"a"})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+
+	_, err = TryStringMap(map[struct{}]int{{}: 1})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+}
+
+func Test_TryStringMapString(t *testing.T) {
+	expected := map[string]string{"a": "1", "b": "x"}
+	for _, in := range []interface{}{
+		map[string]interface{}{"a": 1, "b": "x"},
+		map[string]string{"a": "1", "b": "x"},
+		`{"a": 1, "b": "x"}`,
+		"a=1,b=x",
+		" a = 1 , b=x",
+	} {
+		casted, err := TryStringMapString(in)
+		require.NoError(t, err, "%#v", in)
+		require.Equal(t, expected, casted, "%#v", in)
+	}
+
+	casted, err := TryStringMapString("a=1;b=x=y", WithSeparator(";"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "1", "b": "x=y"}, casted)
+
+	_, err = TryStringMapString("a=1,b")
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryStringMapString(map[string]interface{}{"a": struct{}{}})
+	var castErr *Error
+	require.True(t, errors.As(err, &castErr))
+	require.Equal(t, `"a"`, castErr.Key)
+}
+
+func testTryStringSlice(t *testing.T, expected []string, in ...interface{}) {
+	for _, inVal := range in {
+		casted, err := TryStringSlice(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.Equal(t, expected, casted, "%#v", inVal)
+	}
+}
+
+func testTryStringSliceErr(t *testing.T, in ...interface{}) {
+	for _, inVal := range in {
+		_, err := TryStringSlice(inVal)
+		require.Error(t, err, "%#v", inVal)
+	}
+}
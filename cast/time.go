@@ -0,0 +1,165 @@
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dateTimeFormat = "2006-01-02T15:04:05"
+	dateFormat     = "2006-01-02"
+
+	timeTarget = "time.Time"
+)
+
+// dateTimeLayouts is a built-in chain of layouts tried in order, fractional seconds are optional in every layout
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	dateFormat,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+var (
+	layoutsMu         sync.RWMutex
+	registeredLayouts []string
+)
+
+// RegisterLayout adds layouts to the chain used by TryDate and TryDateTime,
+// registered layouts are tried before the built-in ones
+func RegisterLayout(layouts ...string) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	registeredLayouts = append(registeredLayouts, layouts...)
+}
+
+// TryDate casts value to time.Time, strings are parsed with layouts passed by WithLayouts,
+// then with the date layout 2006-01-02 and then with the rest of the chain used by TryDateTime
+func TryDate(value interface{}, opts ...Option) (time.Time, error) {
+	o := newOptions(opts)
+	o.layouts = append(o.layouts, dateFormat)
+	return tryTime(value, o)
+}
+
+// TryDateTime casts value to time.Time. Strings are parsed with layouts passed by WithLayouts,
+// then with registered ones (see RegisterLayout) and then with built-in chain which includes
+// RFC3339 with or without fractional seconds and zone. Zone-less strings are parsed in location
+// set by WithLocation. Numbers and numeric strings are treated as Unix timestamps in seconds,
+// milliseconds, microseconds or nanoseconds depending on their magnitude (see WithEpochUnit)
+func TryDateTime(value interface{}, opts ...Option) (time.Time, error) {
+	return tryTime(value, newOptions(opts))
+}
+
+func tryTime(value interface{}, o *options) (time.Time, error) {
+	value = indirect(value)
+
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return parseTime(value, v, o)
+	case []byte:
+		return parseTime(value, string(v), o)
+	case float64:
+		return floatToTime(value, v, o)
+	case float32:
+		return floatToTime(value, float64(v), o)
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		n, err := TryInt64(v)
+		if err != nil {
+			return time.Time{}, overflowError(value, timeTarget)
+		}
+		return intToTime(value, n, o)
+	default:
+		converted, ok, err := fallback(value, timeTarget)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !ok {
+			return time.Time{}, unsupportedError(value, timeTarget)
+		}
+		return tryTime(converted, o)
+	}
+}
+
+func parseTime(value interface{}, s string, o *options) (time.Time, error) {
+	trimmed := strings.TrimSpace(s)
+
+	layoutsMu.RLock()
+	registered := registeredLayouts
+	layoutsMu.RUnlock()
+
+	for _, chain := range [][]string{o.layouts, registered, dateTimeLayouts} {
+		for _, layout := range chain {
+			if t, err := time.ParseInLocation(layout, trimmed, o.location); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		return intToTime(value, n, o)
+	}
+	if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return floatToTime(value, f, o)
+	}
+
+	return time.Time{}, syntaxError(value, timeTarget, "no layout matched")
+}
+
+func intToTime(value interface{}, n int64, o *options) (time.Time, error) {
+	unit := o.epochUnit
+	if unit == 0 {
+		unit = guessEpochUnit(math.Abs(float64(n)))
+	}
+
+	perSecond := int64(time.Second / unit)
+	if perSecond == 0 {
+		return time.Time{}, syntaxError(value, timeTarget, "unsupported epoch unit "+unit.String())
+	}
+	return time.Unix(n/perSecond, n%perSecond*int64(unit)).In(o.location), nil
+}
+
+func floatToTime(value interface{}, f float64, o *options) (time.Time, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, syntaxError(value, timeTarget, "not a finite number")
+	}
+
+	unit := o.epochUnit
+	if unit == 0 {
+		unit = guessEpochUnit(math.Abs(f))
+	}
+
+	nanos := f * float64(unit)
+	if nanos < math.MinInt64 || nanos >= math.MaxInt64 {
+		return time.Time{}, overflowError(value, timeTarget)
+	}
+	return time.Unix(0, int64(nanos)).In(o.location), nil
+}
+
+// guessEpochUnit guesses the unit of Unix timestamp, seconds cover dates up to year 5138
+func guessEpochUnit(abs float64) time.Duration {
+	switch {
+	case abs < 1e11:
+		return time.Second
+	case abs < 1e14:
+		return time.Millisecond
+	case abs < 1e17:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
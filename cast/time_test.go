@@ -0,0 +1,96 @@
+package cast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryDateTime_Layouts(t *testing.T) {
+	expected := time.Date(2019, 3, 27, 11, 10, 14, 640000000, time.UTC)
+	testTryDateTime(t, expected,
+		"2019-03-27T11:10:14.640000",
+		"2019-03-27T11:10:14.64Z",
+		"2019-03-27T14:10:14.640+03:00",
+		"2019-03-27 11:10:14.64",
+		" 2019-03-27T11:10:14.640000 ",
+		[]byte("2019-03-27T11:10:14.640000"),
+	)
+
+	testTryDateTime(t, time.Date(2019, 3, 27, 11, 10, 14, 0, time.UTC),
+		"2019-03-27T11:10:14",
+		"Wed, 27 Mar 2019 11:10:14 +0000",
+	)
+
+	testTryDateTimeErr(t, "", "2019-13-27", "yesterday", struct{}{}, true)
+}
+
+func Test_TryDateTime_Epoch(t *testing.T) {
+	seconds := time.Date(2019, 3, 27, 8, 10, 14, 0, time.UTC)
+	testTryDateTime(t, seconds, 1553674214, int64(1553674214), uint32(1553674214), "1553674214", 1553674214.0)
+
+	millis := time.Date(2019, 3, 27, 8, 10, 14, 640000000, time.UTC)
+	testTryDateTime(t, millis, int64(1553674214640), "1553674214640", 1553674214640.0, 1553674214.64)
+
+	micros := time.Date(2019, 3, 27, 8, 10, 14, 640123000, time.UTC)
+	testTryDateTime(t, micros, int64(1553674214640123))
+
+	nanos := time.Date(2019, 3, 27, 8, 10, 14, 640123456, time.UTC)
+	testTryDateTime(t, nanos, int64(1553674214640123456))
+
+	parsed, err := TryDateTime(1000, WithEpochUnit(time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1, 0).UTC(), parsed)
+}
+
+func Test_TryDateTime_Location(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+
+	parsed, err := TryDateTime("2019-03-27T11:10:14.640000", WithLocation(loc))
+	require.NoError(t, err)
+	require.Equal(t, loc, parsed.Location())
+	require.True(t, time.Date(2019, 3, 27, 8, 10, 14, 640000000, time.UTC).Equal(parsed))
+
+	parsed, err = TryDateTime("2019-03-27T11:10:14Z", WithLocation(loc))
+	require.NoError(t, err)
+	require.True(t, time.Date(2019, 3, 27, 11, 10, 14, 0, time.UTC).Equal(parsed))
+
+	parsed, err = TryDateTime(1553674214, WithLocation(loc))
+	require.NoError(t, err)
+	require.Equal(t, loc, parsed.Location())
+}
+
+func Test_RegisterLayout(t *testing.T) {
+	_, err := TryDateTime("27/03/2019 11:10")
+	require.Error(t, err)
+
+	layoutsMu.Lock()
+	saved := registeredLayouts
+	layoutsMu.Unlock()
+	defer func() {
+		layoutsMu.Lock()
+		registeredLayouts = saved
+		layoutsMu.Unlock()
+	}()
+
+	RegisterLayout("02/01/2006 15:04")
+	parsed, err := TryDateTime("27/03/2019 11:10")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2019, 3, 27, 11, 10, 0, 0, time.UTC), parsed)
+}
+
+func testTryDateTime(t *testing.T, expected time.Time, in ...interface{}) {
+	for _, inVal := range in {
+		parsed, err := TryDateTime(inVal)
+		require.NoError(t, err, "%#v", inVal)
+		require.True(t, expected.Equal(parsed), "%#v: expected %s, got %s", inVal, expected, parsed)
+	}
+}
+
+func testTryDateTimeErr(t *testing.T, in ...interface{}) {
+	for _, inVal := range in {
+		_, err := TryDateTime(inVal)
+		require.Error(t, err, "%#v", inVal)
+	}
+}
@@ -0,0 +1,67 @@
+package cast
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const uuidTarget = "uuid.UUID"
+
+// TryUUID casts value to UUID string in canonical lowercase form, accepted values are the same as in TryUUIDValue
+func TryUUID(value interface{}, opts ...Option) (string, error) {
+	u, err := TryUUIDValue(value, opts...)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// TryUUIDValue casts value to uuid.UUID. Accepted values are uuid.UUID, 16-byte arrays and slices and
+// strings in canonical form, with or without dashes, in braces or with urn:uuid: prefix.
+// Versions may be restricted with WithUUIDVersion
+func TryUUIDValue(value interface{}, opts ...Option) (uuid.UUID, error) {
+	value = indirect(value)
+
+	var (
+		u   uuid.UUID
+		err error
+	)
+	switch castedVal := value.(type) {
+	case uuid.UUID:
+		u = castedVal
+	case [16]byte:
+		u = castedVal
+	case []byte:
+		if len(castedVal) == 16 {
+			u, err = uuid.FromBytes(castedVal)
+		} else {
+			u, err = uuid.ParseBytes(castedVal)
+		}
+	case string:
+		u, err = uuid.Parse(strings.TrimSpace(castedVal))
+	default:
+		s, strErr := TryString(value)
+		if strErr != nil {
+			return uuid.Nil, unsupportedError(value, uuidTarget)
+		}
+		u, err = uuid.Parse(strings.TrimSpace(s))
+	}
+	if err != nil {
+		return uuid.Nil, newError(value, uuidTarget, KindSyntax, err)
+	}
+
+	if o := newOptions(opts); len(o.uuidVersions) > 0 && !hasVersion(o.uuidVersions, u.Version()) {
+		return uuid.Nil, syntaxError(value, uuidTarget, "unexpected "+u.Version().String())
+	}
+	return u, nil
+}
+
+func hasVersion(versions []uuid.Version, v uuid.Version) bool {
+	for _, version := range versions {
+		if version == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package cast
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TryUUIDValue(t *testing.T) {
+	expected := uuid.MustParse("523bbaf3-7ef1-4f4a-8713-ab8217a8f182")
+	bytes := [16]byte(expected)
+
+	for _, in := range []interface{}{
+		"523bbaf3-7ef1-4f4a-8713-ab8217a8f182",
+		"523BBAF3-7EF1-4F4A-8713-AB8217A8F182",
+		"{523bbaf3-7ef1-4f4a-8713-ab8217a8f182}",
+		"urn:uuid:523bbaf3-7ef1-4f4a-8713-ab8217a8f182",
+		"523bbaf37ef14f4a8713ab8217a8f182",
+		" 523bbaf3-7ef1-4f4a-8713-ab8217a8f182 ",
+		[]byte("523bbaf3-7ef1-4f4a-8713-ab8217a8f182"),
+		bytes[:],
+		bytes,
+		expected,
+		&expected,
+	} {
+		u, err := TryUUIDValue(in)
+		require.NoError(t, err, "%#v", in)
+		require.Equal(t, expected, u, "%#v", in)
+
+		s, err := TryUUID(in)
+		require.NoError(t, err, "%#v", in)
+		require.Equal(t, "523bbaf3-7ef1-4f4a-8713-ab8217a8f182", s, "%#v", in)
+	}
+
+	for _, in := range []interface{}{"", "not an uuid", []byte{1, 2, 3}, 123, struct{}{}, "523bbaf3-7ef1-4f4a-8713-ab8217a8f18"} {
+		_, err := TryUUIDValue(in)
+		require.Error(t, err, "%#v", in)
+	}
+
+	_, err := TryUUIDValue(struct{}{})
+	require.True(t, errors.Is(err, ErrUnsupportedType))
+}
+
+func Test_TryUUIDValue_Version(t *testing.T) {
+	v4 := "523bbaf3-7ef1-4f4a-8713-ab8217a8f182"
+	v1 := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	u, err := TryUUIDValue(v4, WithUUIDVersion(4))
+	require.NoError(t, err)
+	require.Equal(t, uuid.Version(4), u.Version())
+
+	_, err = TryUUIDValue(v1, WithUUIDVersion(4))
+	require.True(t, errors.Is(err, ErrSyntax))
+
+	_, err = TryUUID(v1, WithUUIDVersion(1, 4))
+	require.NoError(t, err)
+}
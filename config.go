@@ -1,41 +1,120 @@
 package util
 
 import (
-	"bufio"
-	"flag"
+	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
-//InitEnvironmentIfNeeded set env variables listed in file
+// InitEnvironmentIfNeeded set env variables listed in file, see ParseEnv for the file syntax.
+// Path to file is taken from flag flagName of os.Args, existing variables are overridden
 func InitEnvironmentIfNeeded(flagName string) error {
-	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
-	pathToEnv := flagSet.String(flagName, "", "Path to file with env values")
-	if err := flagSet.Parse(os.Args[1:]); err != nil {
-		return nil
-	}
+	return InitEnvironment(os.Args[1:], flagName, true)
+}
 
-	if len(*pathToEnv) == 0 {
-		return nil
+// InitEnvironment sets env variables listed in file given by flag flagName in args.
+// Existing variables are kept unless override is set
+func InitEnvironment(args []string, flagName string, override bool) error {
+	path, err := EnvFileFromArgs(args, flagName)
+	if err != nil || path == "" {
+		return err
 	}
 
-	file, err := os.Open(*pathToEnv)
+	vars, err := ReadEnvFile(path, os.LookupEnv)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	return ApplyEnv(vars, override)
+}
 
-	scanner := bufio.NewScanner(file)
+// EnvFileFromArgs returns value of flag flagName given as -name value, -name=value or with double dash,
+// empty string if there is no such flag. Other flags are ignored
+func EnvFileFromArgs(args []string, flagName string) (string, error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
 
-	for scanner.Scan() {
-		s := strings.SplitN(scanner.Text(), "=", 2)
-		if len(s) < 2 {
-			//skip empty lines
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if strings.HasPrefix(name, flagName+"=") {
+			return name[len(flagName)+1:], nil
+		}
+		if name != flagName {
 			continue
 		}
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("flag needs an argument: -%s", flagName)
+		}
+		return args[i+1], nil
+	}
+	return "", nil
+}
 
-		_ = os.Setenv(s[0], s[1])
+// ReadEnvFile parses env file without changing the process environment,
+// lookup resolves interpolated variables not defined in the file and may be nil
+func ReadEnvFile(path string, lookup func(key string) (string, bool)) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
+	defer file.Close()
+
+	vars, err := ParseEnv(file, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vars, nil
+}
 
-	return scanner.Err()
+// MapLookup returns lookup function over vars which falls back to fallback if it is not nil, e.g.
+//
+//	err := util.LoadConfig(&cfg, util.WithLookup(util.MapLookup(vars, os.LookupEnv)))
+func MapLookup(vars map[string]string, fallback func(key string) (string, bool)) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		if value, ok := vars[key]; ok {
+			return value, true
+		}
+		if fallback != nil {
+			return fallback(key)
+		}
+		return "", false
+	}
+}
+
+// InvalidEnvKeysError lists keys which can't be set as env variables
+type InvalidEnvKeysError struct {
+	Keys []string
+}
+
+func (e *InvalidEnvKeysError) Error() string {
+	return "invalid env keys: " + strings.Join(e.Keys, ", ")
+}
+
+// ApplyEnv sets vars in the process environment, variables which are already set are kept unless override is set.
+// Invalid keys are skipped and reported with *InvalidEnvKeysError
+func ApplyEnv(vars map[string]string, override bool) error {
+	var invalid []string
+	for key, value := range vars {
+		if !isValidEnvKey(key) {
+			invalid = append(invalid, key)
+			continue
+		}
+		if _, exists := os.LookupEnv(key); exists && !override {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return &InvalidEnvKeysError{Keys: invalid}
+	}
+	return nil
 }
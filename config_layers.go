@@ -0,0 +1,193 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ConfigValue is the final value of the key with the name of the source it came from
+type ConfigValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	// Secret is set if the value was resolved from the secret reference
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Config merges values of layered sources, sources listed later override the earlier ones, e.g.
+//
+//	cfg := util.NewConfig(util.StandardConfigSources(os.Getenv("PROFILE"), os.Args[1:])...)
+//	if err := cfg.Load(); err != nil {...}
+//	err := cfg.Bind(&serviceConfig)
+type Config struct {
+	sources []ConfigSource
+
+	reloadMu   sync.Mutex
+	validator  func(lookup func(key string) (string, bool)) error
+	secretRefs *Secrets
+	secretKeys []string
+	stamps     map[string]fileStamp
+
+	mu          sync.RWMutex
+	values      map[string]ConfigValue
+	secrets     []string
+	subscribers map[int]func([]ConfigChange)
+	nextID      int
+}
+
+// NewConfig returns config with the given sources, call Load to read them
+func NewConfig(sources ...ConfigSource) *Config {
+	return &Config{
+		sources:     sources,
+		values:      make(map[string]ConfigValue),
+		subscribers: make(map[int]func([]ConfigChange)),
+	}
+}
+
+// Load reads all sources and replaces current values, on error current values are kept.
+// Env files interpolate variables from the layers below and then from the process environment
+func (c *Config) Load() error {
+	_, err := c.Reload()
+	return err
+}
+
+func (c *Config) merge() (map[string]ConfigValue, error) {
+	values := make(map[string]ConfigValue)
+	lookup := func(key string) (string, bool) {
+		if v, ok := values[key]; ok {
+			return v.Value, true
+		}
+		return os.LookupEnv(key)
+	}
+
+	for _, source := range c.sources {
+		loaded, err := source.Load(lookup)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range loaded {
+			values[key] = ConfigValue{Value: value, Source: source.Name()}
+		}
+	}
+
+	if c.secretRefs != nil {
+		for _, key := range c.secretKeys {
+			v, found := values[key]
+			if !found {
+				continue
+			}
+			secret, ok, err := c.secretRefs.Resolve(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if ok {
+				values[key] = ConfigValue{Value: secret, Source: v.Source, Secret: true}
+			}
+		}
+	}
+	return values, nil
+}
+
+// SetSecrets makes Load resolve secret references in the merged values of the keys,
+// values of other keys are never resolved whatever source they come from
+func (c *Config) SetSecrets(secrets *Secrets, keys ...string) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.secretRefs = secrets
+	c.secretKeys = keys
+}
+
+// Lookup returns value of the key, it is suitable for WithLookup
+func (c *Config) Lookup(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := c.values[key]
+	return v.Value, ok
+}
+
+// Source returns name of the source the value of the key came from
+func (c *Config) Source(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := c.values[key]
+	return v.Source, ok
+}
+
+// Values returns copy of all values with their sources
+func (c *Config) Values() map[string]ConfigValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	values := make(map[string]ConfigValue, len(c.values))
+	for key, v := range c.values {
+		values[key] = v
+	}
+	return values
+}
+
+// Bind populates struct cfg points to from config values, see LoadConfig
+func (c *Config) Bind(cfg interface{}, opts ...ConfigOption) error {
+	return LoadConfig(cfg, append([]ConfigOption{WithLookup(c.Lookup)}, opts...)...)
+}
+
+// MarkSecret makes Report mask values of the keys in addition to keys recognized by IsSecretKey
+func (c *Config) MarkSecret(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.secrets = append(c.secrets, keys...)
+}
+
+const maskedValue = "******"
+
+// Report returns all values with their sources, resolved secrets and values of secret keys are masked
+func (c *Config) Report() map[string]ConfigValue {
+	values := c.Values()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for key, v := range values {
+		if (v.Secret || c.isSecret(key)) && v.Value != "" {
+			v.Value = maskedValue
+			values[key] = v
+		}
+	}
+	return values
+}
+
+func (c *Config) isSecret(key string) bool {
+	for _, secret := range c.secrets {
+		if secret == key {
+			return true
+		}
+	}
+	return IsSecretKey(key)
+}
+
+var secretKeyParts = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "PRIVATE_KEY", "CREDENTIAL"}
+
+// IsSecretKey reports whether the key looks like it holds a secret, e.g. DB_PASSWORD or GITHUB_TOKEN
+func IsSecretKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler returns handler for the /config debug endpoint which responds with Report in json
+func (c *Config) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(contentType, applicationJSONCharsetUTF8)
+		_ = json.NewEncoder(w).Encode(c.Report())
+	})
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestConfigLayers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	envFile := writeTestFile(t, dir, ".env", "HOST=env-file\nPORT=8080\nDB_PASSWORD=secret\n")
+	localFile := writeTestFile(t, dir, ".env.local", "PORT=9090\nURL=http://${HOST}:${PORT}\n")
+	jsonFile := writeTestFile(t, dir, "config.json", `{"db": {"max-conns": 20, "hosts": ["a", "b"]}, "debug": true}`)
+	yamlFile := writeTestFile(t, dir, "config.yaml", "db:\n  max-conns: 30\nlevel: info\n")
+
+	cfg := NewConfig(
+		DefaultsSource(map[string]string{"HOST": "default", "LEVEL": "debug", "TIMEOUT": "1s"}),
+		EnvFileSource(envFile, false),
+		EnvFileSource(localFile, false),
+		EnvFileSource(filepath.Join(dir, ".env.missing"), true),
+		JSONFileSource(jsonFile, false),
+		YAMLFileSource(yamlFile, false),
+		FlagSource([]string{"--timeout=5s", "--verbose", "--level", "warn", "positional", "--ignored"}),
+	)
+	require.NoError(t, cfg.Load())
+
+	assert.Equal(t, map[string]ConfigValue{
+		"HOST":         {Value: "env-file", Source: envFile},
+		"PORT":         {Value: "9090", Source: localFile},
+		"URL":          {Value: "http://env-file:9090", Source: localFile},
+		"DB_PASSWORD":  {Value: "secret", Source: envFile},
+		"DB_MAX_CONNS": {Value: "30", Source: yamlFile},
+		"DB_HOSTS":     {Value: "a,b", Source: jsonFile},
+		"DEBUG":        {Value: "true", Source: jsonFile},
+		"LEVEL":        {Value: "warn", Source: "flags"},
+		"TIMEOUT":      {Value: "5s", Source: "flags"},
+		"VERBOSE":      {Value: "true", Source: "flags"},
+	}, cfg.Values())
+
+	var bound struct {
+		MaxConns int      `env:"DB_MAX_CONNS"`
+		Hosts    []string `env:"DB_HOSTS"`
+	}
+	require.NoError(t, cfg.Bind(&bound))
+	assert.Equal(t, 30, bound.MaxConns)
+	assert.Equal(t, []string{"a", "b"}, bound.Hosts)
+
+	cfg.MarkSecret("URL")
+	rec := httptest.NewRecorder()
+	cfg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/config", nil))
+
+	var report map[string]ConfigValue
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, ConfigValue{Value: maskedValue, Source: envFile}, report["DB_PASSWORD"])
+	assert.Equal(t, maskedValue, report["URL"].Value)
+	assert.Equal(t, "9090", report["PORT"].Value)
+}
+
+func TestConfigLoadKeepsValuesOnError(t *testing.T) {
+	cfg := NewConfig(DefaultsSource(map[string]string{"A": "1"}))
+	require.NoError(t, cfg.Load())
+
+	cfg.sources = append(cfg.sources, EnvFileSource(filepath.Join(os.TempDir(), "missing.env"), false))
+	assert.Error(t, cfg.Load())
+
+	value, ok := cfg.Lookup("A")
+	assert.True(t, ok)
+	assert.Equal(t, "1", value)
+}
@@ -0,0 +1,263 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/itimofeev/go-util/cast"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigSource provides configuration values to Config
+type ConfigSource interface {
+	// Name identifies the source in the config report, e.g. path of the file
+	Name() string
+	// Load returns values of the source, lookup resolves values of the layers below for interpolation
+	Load(lookup func(key string) (string, bool)) (map[string]string, error)
+}
+
+type defaultsSource map[string]string
+
+// DefaultsSource returns source with fixed values
+func DefaultsSource(values map[string]string) ConfigSource {
+	return defaultsSource(values)
+}
+
+func (s defaultsSource) Name() string {
+	return "defaults"
+}
+
+func (s defaultsSource) Load(func(key string) (string, bool)) (map[string]string, error) {
+	values := make(map[string]string, len(s))
+	for key, value := range s {
+		values[key] = value
+	}
+	return values, nil
+}
+
+type envFileSource struct {
+	path     string
+	optional bool
+}
+
+// EnvFileSource returns source reading env file in dotenv syntax, see ParseEnv.
+// Optional file is skipped if it does not exist
+func EnvFileSource(path string, optional bool) ConfigSource {
+	return &envFileSource{path: path, optional: optional}
+}
+
+func (s *envFileSource) Name() string {
+	return s.path
+}
+
+func (s *envFileSource) Path() string {
+	return s.path
+}
+
+func (s *envFileSource) Load(lookup func(key string) (string, bool)) (map[string]string, error) {
+	values, err := ReadEnvFile(s.path, lookup)
+	if err != nil && s.optional && os.IsNotExist(err) {
+		return nil, nil
+	}
+	return values, err
+}
+
+type structuredFileSource struct {
+	path      string
+	optional  bool
+	unmarshal func(data []byte) (interface{}, error)
+}
+
+// JSONFileSource returns source reading json file. Nested keys are joined with underscore
+// and upper-cased, e.g. {"db": {"max-conns": 10}} gives DB_MAX_CONNS=10, arrays of scalars
+// are joined with comma. Optional file is skipped if it does not exist
+func JSONFileSource(path string, optional bool) ConfigSource {
+	return &structuredFileSource{path: path, optional: optional, unmarshal: unmarshalJSONConfig}
+}
+
+// YAMLFileSource returns source reading yaml file, keys are flattened the same way as by JSONFileSource
+func YAMLFileSource(path string, optional bool) ConfigSource {
+	return &structuredFileSource{path: path, optional: optional, unmarshal: unmarshalYAMLConfig}
+}
+
+func (s *structuredFileSource) Name() string {
+	return s.path
+}
+
+func (s *structuredFileSource) Path() string {
+	return s.path
+}
+
+func (s *structuredFileSource) Load(func(key string) (string, bool)) (map[string]string, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if s.optional && os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tree, err := s.unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfig("", tree, values); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return values, nil
+}
+
+func unmarshalJSONConfig(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var tree interface{}
+	err := decoder.Decode(&tree)
+	return tree, err
+}
+
+func unmarshalYAMLConfig(data []byte) (interface{}, error) {
+	var tree interface{}
+	err := yaml.Unmarshal(data, &tree)
+	return tree, err
+}
+
+func flattenConfig(key string, node interface{}, values map[string]string) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, child := range n {
+			if err := flattenConfig(joinConfigKey(key, k), child, values); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		for k, child := range n {
+			if err := flattenConfig(joinConfigKey(key, fmt.Sprint(k)), child, values); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items := make([]string, 0, len(n))
+		for i, child := range n {
+			switch child.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+				if err := flattenConfig(joinConfigKey(key, strconv.Itoa(i)), child, values); err != nil {
+					return err
+				}
+			default:
+				s, err := configScalar(child)
+				if err != nil {
+					return err
+				}
+				items = append(items, s)
+			}
+		}
+		if len(items) > 0 {
+			values[key] = strings.Join(items, ",")
+		}
+	default:
+		if key == "" {
+			return fmt.Errorf("expected object at the top level, got %T", node)
+		}
+		s, err := configScalar(n)
+		if err != nil {
+			return err
+		}
+		values[key] = s
+	}
+	return nil
+}
+
+func configScalar(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	return cast.TryString(value)
+}
+
+func joinConfigKey(prefix, key string) string {
+	key = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(key))
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+type envSource struct{}
+
+// EnvSource returns source with variables of the process environment
+func EnvSource() ConfigSource {
+	return envSource{}
+}
+
+func (envSource) Name() string {
+	return "env"
+}
+
+func (envSource) Load(func(key string) (string, bool)) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if i := strings.Index(kv, "="); i > 0 {
+			values[kv[:i]] = kv[i+1:]
+		}
+	}
+	return values, nil
+}
+
+type flagSource []string
+
+// FlagSource returns source with command-line flags given as --name=value or --name value,
+// flag without value is set to true. Names are upper-cased with dashes replaced by underscores,
+// e.g. --db-url gives DB_URL. Parsing stops at the first non-flag argument or at "--"
+func FlagSource(args []string) ConfigSource {
+	return flagSource(args)
+}
+
+func (flagSource) Name() string {
+	return "flags"
+}
+
+func (s flagSource) Load(func(key string) (string, bool)) (map[string]string, error) {
+	values := make(map[string]string)
+	for i := 0; i < len(s); i++ {
+		arg := s[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value := "true"
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value = name[:j], name[j+1:]
+		} else if i+1 < len(s) && !strings.HasPrefix(s[i+1], "-") {
+			i++
+			value = s[i]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid flag %q", arg)
+		}
+		values[joinConfigKey("", name)] = value
+	}
+	return values, nil
+}
+
+// StandardConfigSources returns sources in the usual precedence, the later overrides the earlier:
+// .env, .env.local, .env.<profile> (if profile is not empty), the process environment and flags from args.
+// All env files are optional
+func StandardConfigSources(profile string, args []string) []ConfigSource {
+	sources := []ConfigSource{
+		EnvFileSource(".env", true),
+		EnvFileSource(".env.local", true),
+	}
+	if profile != "" {
+		sources = append(sources, EnvFileSource(".env."+profile, true))
+	}
+	return append(sources, EnvSource(), FlagSource(args))
+}
@@ -0,0 +1,250 @@
+package util
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/itimofeev/go-util/cast"
+)
+
+// ErrConfigMissing is reported for required variable which is not set
+var ErrConfigMissing = errors.New("required variable is not set")
+
+// ConfigFieldError describes the variable which failed to load into the config field
+type ConfigFieldError struct {
+	Key   string
+	Field string
+	Err   error
+}
+
+func (e *ConfigFieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", e.Key, e.Field, e.Err)
+}
+
+func (e *ConfigFieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError lists every variable LoadConfig failed to load
+type ConfigError struct {
+	Errors []*ConfigFieldError
+}
+
+func (e *ConfigError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "invalid config: " + strings.Join(messages, "; ")
+}
+
+// ConfigOption configures LoadConfig
+type ConfigOption func(*configOptions)
+
+type configOptions struct {
+	lookup   func(key string) (string, bool)
+	castOpts []cast.Option
+	secrets  *Secrets
+}
+
+func newConfigOptions(opts []ConfigOption) *configOptions {
+	o := &configOptions{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithLookup sets the function values of variables are taken from, os.LookupEnv by default
+func WithLookup(lookup func(key string) (string, bool)) ConfigOption {
+	return func(o *configOptions) {
+		o.lookup = lookup
+	}
+}
+
+// WithCastOptions sets options used to convert values, e.g. cast.WithDurationUnit
+func WithCastOptions(opts ...cast.Option) ConfigOption {
+	return func(o *configOptions) {
+		o.castOpts = append(o.castOpts, opts...)
+	}
+}
+
+// WithSecrets makes LoadConfig resolve secret references in values of fields tagged secret:"true", see Secrets
+func WithSecrets(secrets *Secrets) ConfigOption {
+	return func(o *configOptions) {
+		o.secrets = secrets
+	}
+}
+
+// LoadConfig populates struct cfg points to from environment variables described by field tags:
+//
+//	type Config struct {
+//		DBURL   string        `env:"DB_URL" required:"true"`
+//		Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+//		Hosts   []string      `env:"HOSTS" sep:";"`
+//		Port    int           `env:"PORT" validate:"min=1,max=65535"`
+//		Token   string        `env:"AUTH" secret:"true"`
+//		Cache   CacheConfig   `prefix:"CACHE_"`
+//	}
+//
+// Values are converted with cast.TryInto, fields of nested structs are loaded from variables
+// with the prefix prepended. Nil pointer to nested struct is allocated only if some of its
+// variables are set, otherwise it stays nil. Default is used if variable is unset or empty.
+// Loaded values are checked with validate rules, see ValidateConfig.
+// Tag secret:"true" masks the value in reports in addition to keys recognized by IsSecretKey
+// and lets WithSecrets resolve references in the value.
+// The returned *ConfigError lists every missing or invalid variable
+func LoadConfig(cfg interface{}, opts ...ConfigOption) error {
+	_, err := loadConfig(cfg, opts)
+	return err
+}
+
+func loadConfig(cfg interface{}, opts []ConfigOption) ([]*configField, error) {
+	o := newConfigOptions(opts)
+
+	fields, err := configFields(cfg, o.lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var configErr ConfigError
+	for _, field := range fields {
+		err := field.load(o)
+		if err == nil {
+			err = field.validate()
+		}
+		if err != nil {
+			configErr.Errors = append(configErr.Errors, &ConfigFieldError{Key: field.key, Field: field.path, Err: err})
+		}
+	}
+
+	if len(configErr.Errors) > 0 {
+		return fields, &configErr
+	}
+	return fields, nil
+}
+
+// configField is the struct field bound to the variable
+type configField struct {
+	key    string
+	path   string
+	tag    reflect.StructTag
+	value  reflect.Value
+	secret bool
+}
+
+func (f *configField) load(o *configOptions) error {
+	raw, ok := o.lookup(f.key)
+	if !ok || raw == "" {
+		raw, ok = f.tag.Lookup("default")
+	}
+	if !ok || raw == "" {
+		if f.tag.Get("required") == "true" {
+			return ErrConfigMissing
+		}
+		return nil
+	}
+
+	if o.secrets != nil && f.tag.Get("secret") == "true" {
+		secret, _, err := o.secrets.Resolve(raw)
+		if err != nil {
+			return err
+		}
+		raw = secret
+	}
+
+	castOpts := o.castOpts
+	if sep, ok := f.tag.Lookup("sep"); ok {
+		castOpts = append(castOpts[:len(castOpts):len(castOpts)], cast.WithSeparator(sep))
+	}
+	return cast.TryInto(raw, f.value.Addr().Interface(), castOpts...)
+}
+
+// configFields returns fields of struct cfg points to tagged with env, nested structs included.
+// Nil pointers to nested structs are allocated if lookup finds any of their variables and skipped otherwise
+func configFields(cfg interface{}, lookup func(key string) (string, bool)) ([]*configField, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a non-nil pointer to struct, got %T", cfg)
+	}
+
+	var fields []*configField
+	collectConfigFields(v.Elem(), "", "", lookup, &fields)
+	return fields, nil
+}
+
+func collectConfigFields(v reflect.Value, prefix, path string, lookup func(key string) (string, bool), fields *[]*configField) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		fv := v.Field(i)
+
+		if key, ok := sf.Tag.Lookup("env"); ok {
+			if key != "-" {
+				*fields = append(*fields, &configField{
+					key:    prefix + key,
+					path:   fieldPath,
+					tag:    sf.Tag,
+					value:  fv,
+					secret: sf.Tag.Get("secret") == "true" || IsSecretKey(prefix+key),
+				})
+			}
+			continue
+		}
+
+		if !isNestedConfig(sf.Type) {
+			continue
+		}
+		nestedPrefix := prefix + sf.Tag.Get("prefix")
+		if sf.Type.Kind() != reflect.Ptr {
+			collectConfigFields(fv, nestedPrefix, fieldPath, lookup, fields)
+			continue
+		}
+		if !fv.IsNil() {
+			collectConfigFields(fv.Elem(), nestedPrefix, fieldPath, lookup, fields)
+			continue
+		}
+		if lookup == nil {
+			continue
+		}
+
+		nested := reflect.New(sf.Type.Elem())
+		var nestedFields []*configField
+		collectConfigFields(nested.Elem(), nestedPrefix, fieldPath, lookup, &nestedFields)
+		if anyConfigKeySet(nestedFields, lookup) {
+			fv.Set(nested)
+			*fields = append(*fields, nestedFields...)
+		}
+	}
+}
+
+func anyConfigKeySet(fields []*configField, lookup func(key string) (string, bool)) bool {
+	for _, field := range fields {
+		if _, ok := lookup(field.key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func isNestedConfig(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}) && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDBConfig struct {
+	URL      string `env:"URL" required:"true"`
+	MaxConns int    `env:"MAX_CONNS" default:"10"`
+}
+
+type testConfig struct {
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Hosts    []string      `env:"HOSTS" sep:";"`
+	Ports    []int         `env:"PORTS"`
+	Ignored  string        `env:"-"`
+	Untagged string
+	DB       testDBConfig  `prefix:"DB_"`
+	Replica  *testDBConfig `prefix:"REPLICA_"`
+}
+
+func TestLoadConfig(t *testing.T) {
+	var cfg testConfig
+	err := LoadConfig(&cfg, WithLookup(MapLookup(map[string]string{
+		"DEBUG":       "yes",
+		"HOSTS":       "a;b",
+		"PORTS":       "80,443",
+		"DB_URL":      "postgres://db",
+		"REPLICA_URL": "postgres://replica",
+		"Untagged":    "ignored",
+	}, nil)))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	assert.Empty(t, cfg.Untagged)
+	assert.Equal(t, testDBConfig{URL: "postgres://db", MaxConns: 10}, cfg.DB)
+	require.NotNil(t, cfg.Replica)
+	assert.Equal(t, "postgres://replica", cfg.Replica.URL)
+}
+
+func TestLoadConfigAbsentNestedPointer(t *testing.T) {
+	var cfg testConfig
+	err := LoadConfig(&cfg, WithLookup(MapLookup(map[string]string{
+		"DB_URL": "postgres://db",
+	}, nil)))
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Replica)
+
+	require.NoError(t, ValidateConfig(&cfg))
+	assert.Nil(t, cfg.Replica)
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	var cfg testConfig
+	err := LoadConfig(&cfg, WithLookup(MapLookup(map[string]string{
+		"DEBUG":        "maybe",
+		"PORTS":        "80,http",
+		"DB_MAX_CONNS": "many",
+		"REPLICA_URL":  "postgres://replica",
+	}, nil)))
+
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+
+	keys := make([]string, 0, len(configErr.Errors))
+	for _, fieldErr := range configErr.Errors {
+		keys = append(keys, fieldErr.Key)
+	}
+	assert.Equal(t, []string{"DEBUG", "PORTS", "DB_URL", "DB_MAX_CONNS"}, keys)
+	assert.True(t, errors.Is(configErr.Errors[2], ErrConfigMissing))
+	assert.Contains(t, err.Error(), "DB_URL (DB.URL): required variable is not set")
+
+	assert.Error(t, LoadConfig(cfg))
+}
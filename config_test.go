@@ -0,0 +1,74 @@
+package util
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvFileFromArgs(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+		path string
+		err  bool
+	}{
+		"absent":          {args: []string{"-port", "80"}},
+		"separate value":  {args: []string{"-port=80", "-env", "a.env"}, path: "a.env"},
+		"double dash":     {args: []string{"--env=b.env"}, path: "b.env"},
+		"after separator": {args: []string{"--", "-env", "c.env"}},
+		"missing value":   {args: []string{"serve", "-env"}, err: true},
+		"similar name":    {args: []string{"-envfile", "x", "-env", "d.env"}, path: "d.env"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, err := EnvFileFromArgs(c.args, "env")
+			assert.Equal(t, c.err, err != nil)
+			assert.Equal(t, c.path, path)
+		})
+	}
+}
+
+func TestInitEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestFile(t, dir, "test.env", "UTIL_TEST_KEPT=file\nUTIL_TEST_NEW=${UTIL_TEST_KEPT}\n")
+	require.NoError(t, os.Setenv("UTIL_TEST_KEPT", "env"))
+	defer os.Unsetenv("UTIL_TEST_KEPT")
+	defer os.Unsetenv("UTIL_TEST_NEW")
+
+	require.NoError(t, InitEnvironment([]string{"-env", path}, "env", false))
+	assert.Equal(t, "env", os.Getenv("UTIL_TEST_KEPT"))
+	assert.Equal(t, "file", os.Getenv("UTIL_TEST_NEW"))
+
+	require.NoError(t, InitEnvironment([]string{"-env", path}, "env", true))
+	assert.Equal(t, "file", os.Getenv("UTIL_TEST_KEPT"))
+}
+
+func TestApplyEnvInvalidKeys(t *testing.T) {
+	defer os.Unsetenv("UTIL_TEST_VALID")
+
+	err := ApplyEnv(map[string]string{"UTIL_TEST_VALID": "1", "1BAD": "2", "A=B": "3"}, true)
+
+	var keysErr *InvalidEnvKeysError
+	require.True(t, errors.As(err, &keysErr))
+	assert.Equal(t, []string{"1BAD", "A=B"}, keysErr.Keys)
+	assert.Equal(t, "1", os.Getenv("UTIL_TEST_VALID"))
+}
+
+func TestMapLookup(t *testing.T) {
+	lookup := MapLookup(map[string]string{"A": "1"}, MapLookup(map[string]string{"B": "2"}, nil))
+
+	value, ok := lookup("B")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+
+	_, ok = lookup("C")
+	assert.False(t, ok)
+}
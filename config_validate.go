@@ -0,0 +1,275 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itimofeev/go-util/cast"
+	"github.com/sirupsen/logrus"
+)
+
+// ConfigRuleError reports value violating the validate rule
+type ConfigRuleError struct {
+	Rule string
+	Msg  string
+}
+
+func (e *ConfigRuleError) Error() string {
+	return e.Msg
+}
+
+// ValidateConfig checks fields of struct cfg points to with rules listed in validate tag:
+//
+//	required        value must not be zero
+//	min=N, max=N    bounds of numbers and durations, length of strings, slices and maps
+//	oneof=a b c     value must be one of the space separated options
+//	url             absolute URL
+//	hostport        host:port with numeric port
+//	file-exists     path of the existing file or directory
+//	regex=PATTERN   value must match the pattern, the rule takes the rest of the tag
+//
+// Rules other than required, min and max skip empty values.
+// LoadConfig calls it for the loaded fields, the returned *ConfigError lists every violation
+func ValidateConfig(cfg interface{}) error {
+	fields, err := configFields(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	var configErr ConfigError
+	for _, field := range fields {
+		if err := field.validate(); err != nil {
+			configErr.Errors = append(configErr.Errors, &ConfigFieldError{Key: field.key, Field: field.path, Err: err})
+		}
+	}
+
+	if len(configErr.Errors) > 0 {
+		return &configErr
+	}
+	return nil
+}
+
+// MustLoadConfig loads config with LoadConfig and logs every effective setting with Log, secrets masked.
+// It panics with *ConfigError listing all problems if config is invalid without logging it,
+// so calling it after LogStartStop logs the startup failure once, the same way as any other:
+//
+//	defer util.LogStartStop(util.Log, version, "arp server")()
+//	var cfg Config
+//	util.MustLoadConfig(&cfg)
+func MustLoadConfig(cfg interface{}, opts ...ConfigOption) {
+	fields, err := loadConfig(cfg, opts)
+	if err != nil {
+		panic(err)
+	}
+
+	Log.WithField("config", configReport(fields)).Info("effective config")
+}
+
+// LogConfig logs every setting of struct cfg points to, values of secret fields are masked
+func LogConfig(log logrus.FieldLogger, cfg interface{}) {
+	fields, err := configFields(cfg, nil)
+	if err != nil {
+		log.WithError(err).Error("unable to report config")
+		return
+	}
+	log.WithField("config", configReport(fields)).Info("effective config")
+}
+
+func configReport(fields []*configField) map[string]string {
+	report := make(map[string]string, len(fields))
+	for _, field := range fields {
+		value := displayValue(field.value)
+		if field.secret && value != "" {
+			value = maskedValue
+		}
+		report[field.key] = value
+	}
+	return report
+}
+
+func displayValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 || v.Kind() == reflect.Array {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = displayValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	}
+	if s, err := cast.TryString(v.Interface()); err == nil {
+		return s
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func (f *configField) validate() error {
+	for _, rule := range splitRules(f.tag.Get("validate")) {
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+
+		if err := checkRule(f.value, name, arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regex=") {
+			return append(rules, tag)
+		}
+
+		rule := tag
+		if i := strings.Index(tag, ","); i >= 0 {
+			rule, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func checkRule(v reflect.Value, name, arg string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+
+	switch name {
+	case "required":
+		if v.IsZero() {
+			return &ConfigRuleError{Rule: name, Msg: "must not be empty"}
+		}
+		return nil
+	case "min", "max":
+		return checkBound(v, name, arg)
+	}
+
+	s := displayValue(v)
+	if s == "" {
+		return nil
+	}
+
+	switch name {
+	case "oneof":
+		for _, option := range strings.Fields(arg) {
+			if s == option {
+				return nil
+			}
+		}
+		return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(arg), ", "))}
+	case "url":
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return &ConfigRuleError{Rule: name, Msg: "must be an absolute URL"}
+		}
+	case "hostport":
+		_, port, err := net.SplitHostPort(s)
+		if err == nil {
+			_, err = strconv.ParseUint(port, 10, 16)
+		}
+		if err != nil {
+			return &ConfigRuleError{Rule: name, Msg: "must be host:port"}
+		}
+	case "file-exists":
+		if _, err := os.Stat(s); err != nil {
+			return &ConfigRuleError{Rule: name, Msg: err.Error()}
+		}
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("invalid pattern: %s", err)}
+		}
+		if !re.MatchString(s) {
+			return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("must match %s", arg)}
+		}
+	default:
+		return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("unknown validate rule %q", name)}
+	}
+	return nil
+}
+
+func checkBound(v reflect.Value, name, arg string) error {
+	cmp, what, err := compareBound(v, arg)
+	if err != nil {
+		return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("invalid %s bound %q: %s", name, arg, err)}
+	}
+
+	if name == "min" && cmp < 0 {
+		return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("%s must be at least %s", what, arg)}
+	}
+	if name == "max" && cmp > 0 {
+		return &ConfigRuleError{Rule: name, Msg: fmt.Sprintf("%s must be at most %s", what, arg)}
+	}
+	return nil
+}
+
+// compareBound returns sign of the difference between v and bound and the name of the compared quantity
+func compareBound(v reflect.Value, bound string) (int, string, error) {
+	if v.Type() == durationType {
+		b, err := cast.TryDuration(bound)
+		return compareInt(v.Int(), int64(b)), "value", err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b, err := cast.TryInt64(bound)
+		return compareInt(v.Int(), b), "value", err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b, err := cast.TryUInt64(bound)
+		switch {
+		case v.Uint() < b:
+			return -1, "value", err
+		case v.Uint() > b:
+			return 1, "value", err
+		}
+		return 0, "value", err
+	case reflect.Float32, reflect.Float64:
+		b, err := cast.TryFloat64(bound)
+		switch {
+		case v.Float() < b:
+			return -1, "value", err
+		case v.Float() > b:
+			return 1, "value", err
+		}
+		return 0, "value", err
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		b, err := cast.TryInt64(bound)
+		return compareInt(int64(v.Len()), b), "length", err
+	default:
+		return 0, "", fmt.Errorf("not supported for %s", v.Type())
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testValidatedConfig struct {
+	Name     string        `env:"NAME" validate:"required"`
+	Port     int           `env:"PORT" default:"8080" validate:"min=1,max=65535"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s" validate:"min=1s"`
+	Hosts    []string      `env:"HOSTS" validate:"max=2"`
+	Level    string        `env:"LEVEL" validate:"oneof=debug info warn"`
+	URL      string        `env:"URL" validate:"url"`
+	Addr     string        `env:"ADDR" validate:"hostport"`
+	CertFile string        `env:"CERT_FILE" validate:"file-exists"`
+	Code     string        `env:"CODE" validate:"regex=^[a-z]{2,3}$"`
+	Password string        `env:"DB_PASSWORD"`
+	Token    string        `env:"AUTH" secret:"true"`
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	var cfg testValidatedConfig
+	err := LoadConfig(&cfg, WithLookup(MapLookup(map[string]string{
+		"PORT":      "70000",
+		"TIMEOUT":   "10ms",
+		"HOSTS":     "a,b,c",
+		"LEVEL":     "trace",
+		"URL":       "localhost",
+		"ADDR":      "localhost",
+		"CERT_FILE": "/definitely/missing.pem",
+		"CODE":      "abcd",
+	}, nil)))
+
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+
+	rules := make(map[string]string)
+	for _, fieldErr := range configErr.Errors {
+		var ruleErr *ConfigRuleError
+		require.True(t, errors.As(fieldErr, &ruleErr), fieldErr.Error())
+		rules[fieldErr.Key] = ruleErr.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"NAME":      "required",
+		"PORT":      "max",
+		"TIMEOUT":   "min",
+		"HOSTS":     "max",
+		"LEVEL":     "oneof",
+		"URL":       "url",
+		"ADDR":      "hostport",
+		"CERT_FILE": "file-exists",
+		"CODE":      "regex",
+	}, rules)
+	assert.Contains(t, err.Error(), "PORT (Port): value must be at most 65535")
+}
+
+func TestValidateConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "cert")
+	require.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+
+	cfg := testValidatedConfig{
+		Name:     "app",
+		Port:     8080,
+		Timeout:  time.Second,
+		Level:    "info",
+		URL:      "https://example.com/path",
+		Addr:     "localhost:8080",
+		CertFile: file.Name(),
+		Code:     "ru",
+	}
+	assert.NoError(t, ValidateConfig(&cfg))
+
+	type badRule struct {
+		Value string `env:"VALUE" validate:"unknown"`
+	}
+	assert.Error(t, ValidateConfig(&badRule{Value: "x"}))
+}
+
+func TestMustLoadConfig(t *testing.T) {
+	var out bytes.Buffer
+	Log.Out = &out
+	defer func() { Log.Out = os.Stdout }()
+
+	var cfg testValidatedConfig
+	lookup := WithLookup(MapLookup(map[string]string{"NAME": "app", "HOSTS": "a,b", "DB_PASSWORD": "pass", "AUTH": "token"}, nil))
+	MustLoadConfig(&cfg, lookup)
+	assert.Contains(t, out.String(), `"NAME":"app"`)
+	assert.Contains(t, out.String(), `"HOSTS":"a,b"`)
+	assert.Contains(t, out.String(), `"DB_PASSWORD":"******"`)
+	assert.Contains(t, out.String(), `"AUTH":"******"`)
+	assert.Contains(t, out.String(), "effective config")
+
+	out.Reset()
+	assert.Panics(t, func() {
+		defer LogStartStop(Log, "1.0", "test")()
+		MustLoadConfig(&testValidatedConfig{}, WithLookup(MapLookup(map[string]string{"PORT": "0"}, nil)))
+	})
+	assert.NotContains(t, out.String(), `"msg":"invalid config"`)
+	assert.Contains(t, out.String(), "invalid config: ")
+	assert.Contains(t, out.String(), "NAME (Name): must not be empty")
+	assert.Contains(t, out.String(), "PORT (Port): value must be at least 1")
+	assert.Contains(t, out.String(), "exited with error")
+}
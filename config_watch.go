@@ -0,0 +1,204 @@
+package util
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+)
+
+// ConfigChange describes the key changed by reload
+type ConfigChange struct {
+	Key string
+	// Old is zero if the key was added
+	Old ConfigValue
+	// New is zero if the key was removed
+	New ConfigValue
+}
+
+// SetValidator sets function which checks values before Load or Reload accepts them, e.g.
+//
+//	cfg.SetValidator(func(lookup func(string) (string, bool)) error {
+//		var s ServiceConfig
+//		return util.LoadConfig(&s, util.WithLookup(lookup))
+//	})
+func (c *Config) SetValidator(validator func(lookup func(key string) (string, bool)) error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.validator = validator
+}
+
+// Subscribe registers fn called with changed keys after every reload which changed anything.
+// fn is called synchronously from Reload, the returned function unsubscribes it
+func (c *Config) Subscribe(fn func(changes []ConfigChange)) (unsubscribe func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	c.subscribers[id] = fn
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.subscribers, id)
+	}
+}
+
+// Reload reads all sources, validates the result and replaces current values.
+// On error the last good values are kept. Subscribers are called after the reload completes,
+// so they may call Reload themselves
+func (c *Config) Reload() ([]ConfigChange, error) {
+	changes, subscribers, err := c.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		for _, fn := range subscribers {
+			fn(changes)
+		}
+	}
+	return changes, nil
+}
+
+func (c *Config) reload() ([]ConfigChange, []func([]ConfigChange), error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.stamps = c.fileStamps()
+	values, err := c.merge()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.validator != nil {
+		lookup := func(key string) (string, bool) {
+			v, ok := values[key]
+			return v.Value, ok
+		}
+		if err := c.validator(lookup); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changes := diffConfigValues(c.values, values)
+	c.values = values
+	subscribers := make([]func([]ConfigChange), 0, len(c.subscribers))
+	for _, fn := range c.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	return changes, subscribers, nil
+}
+
+func diffConfigValues(old, new map[string]ConfigValue) []ConfigChange {
+	var changes []ConfigChange
+	for key, newValue := range new {
+		if oldValue, ok := old[key]; !ok || oldValue != newValue {
+			changes = append(changes, ConfigChange{Key: key, Old: oldValue, New: newValue})
+		}
+	}
+	for key, oldValue := range old {
+		if _, ok := new[key]; !ok {
+			changes = append(changes, ConfigChange{Key: key, Old: oldValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// fileSource is implemented by sources read from files, Watch polls them for changes
+type fileSource interface {
+	Path() string
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// Watch polls files of the sources every interval and reloads config when any of them changed
+// since the last load and stayed the same for one interval, so partially written files are not read.
+// It returns when ctx is done. Failed reloads are logged with Log and the last good values are kept
+func (c *Config) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pending map[string]fileStamp
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := c.fileStamps()
+		if !c.filesChanged(current) {
+			pending = nil
+			continue
+		}
+		if !equalFileStamps(pending, current) {
+			pending = current
+			continue
+		}
+		pending = nil
+
+		changes, err := c.Reload()
+		if err != nil {
+			Log.WithError(err).Error("config reload failed, keeping last good config")
+			continue
+		}
+		if len(changes) > 0 {
+			keys := make([]string, 0, len(changes))
+			for _, change := range changes {
+				keys = append(keys, change.Key)
+			}
+			Log.WithField("keys", keys).Info("config reloaded")
+		}
+	}
+}
+
+func (c *Config) filesChanged(current map[string]fileStamp) bool {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	return !equalFileStamps(c.stamps, current)
+}
+
+func (c *Config) fileStamps() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, source := range c.sources {
+		file, ok := source.(fileSource)
+		if !ok {
+			continue
+		}
+
+		var stamp fileStamp
+		if info, err := os.Stat(file.Path()); err == nil {
+			stamp = fileStamp{modTime: info.ModTime(), size: info.Size(), exists: true}
+		}
+		stamps[file.Path()] = stamp
+	}
+	return stamps
+}
+
+func equalFileStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if other, ok := b[path]; !ok || !stamp.modTime.Equal(other.modTime) || stamp.size != other.size || stamp.exists != other.exists {
+			return false
+		}
+	}
+	return true
+}
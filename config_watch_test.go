@@ -0,0 +1,126 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestFile(t, dir, "app.env", "LEVEL=info\nREMOVED=1\n")
+	cfg := NewConfig(DefaultsSource(map[string]string{"NAME": "app"}), EnvFileSource(path, false))
+	require.NoError(t, cfg.Load())
+
+	logged := make(chan *logrus.Entry, 1)
+	hooks := Log.ReplaceHooks(logrus.LevelHooks{})
+	defer Log.ReplaceHooks(hooks)
+	Log.AddHook(chanHook{levels: []logrus.Level{logrus.ErrorLevel}, entries: logged})
+
+	changed := make(chan []ConfigChange, 1)
+	cfg.Subscribe(func(changes []ConfigChange) {
+		changed <- changes
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cfg.Watch(ctx, 5*time.Millisecond)
+
+	writeTestFile(t, dir, "app.env", "LEVEL=debug\nADDED=yes\n")
+	select {
+	case changes := <-changed:
+		assert.Equal(t, []ConfigChange{
+			{Key: "ADDED", New: ConfigValue{Value: "yes", Source: path}},
+			{Key: "LEVEL", Old: ConfigValue{Value: "info", Source: path}, New: ConfigValue{Value: "debug", Source: path}},
+			{Key: "REMOVED", Old: ConfigValue{Value: "1", Source: path}},
+		}, changes)
+	case <-time.After(time.Second):
+		t.Fatal("no changes delivered")
+	}
+
+	writeTestFile(t, dir, "app.env", "LEVEL='unterminated\n")
+	select {
+	case entry := <-logged:
+		assert.Equal(t, "config reload failed, keeping last good config", entry.Message)
+	case <-time.After(time.Second):
+		t.Fatal("failed reload not logged")
+	}
+	level, _ := cfg.Lookup("LEVEL")
+	assert.Equal(t, "debug", level)
+	assert.Empty(t, changed)
+}
+
+func TestConfigReloadValidation(t *testing.T) {
+	values := map[string]string{"PORT": "80"}
+	cfg := NewConfig(DefaultsSource(values))
+	cfg.SetValidator(func(lookup func(string) (string, bool)) error {
+		var s struct {
+			Port int `env:"PORT"`
+		}
+		return LoadConfig(&s, WithLookup(lookup))
+	})
+	require.NoError(t, cfg.Load())
+
+	values["PORT"] = "http"
+	_, err := cfg.Reload()
+	var configErr *ConfigError
+	assert.True(t, errors.As(err, &configErr))
+
+	port, _ := cfg.Lookup("PORT")
+	assert.Equal(t, "80", port)
+
+	unsubscribe := cfg.Subscribe(func([]ConfigChange) {
+		t.Fatal("unsubscribed function called")
+	})
+	unsubscribe()
+
+	values["PORT"] = "8080"
+	changes, err := cfg.Reload()
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
+
+func TestConfigSubscriberReloads(t *testing.T) {
+	values := map[string]string{"PORT": "80"}
+	cfg := NewConfig(DefaultsSource(values))
+	require.NoError(t, cfg.Load())
+
+	reloaded := make(chan error, 1)
+	cfg.Subscribe(func([]ConfigChange) {
+		_, err := cfg.Reload()
+		reloaded <- err
+	})
+
+	values["PORT"] = "8080"
+	_, err := cfg.Reload()
+	require.NoError(t, err)
+	require.NoError(t, <-reloaded)
+}
+
+// chanHook sends entries of the levels to the channel, entries are dropped if the channel is full
+type chanHook struct {
+	levels  []logrus.Level
+	entries chan *logrus.Entry
+}
+
+func (h chanHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h chanHook) Fire(entry *logrus.Entry) error {
+	select {
+	case h.entries <- entry:
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,375 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// EnvSyntaxError describes malformed content of env file
+type EnvSyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *EnvSyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ParseEnv parses env file in dotenv syntax:
+//
+//	# comment
+//	export KEY=value # inline comment
+//	RAW='single quoted, no escapes and no ${INTERPOLATION}'
+//	TEXT="double quoted\twith escapes
+//	and multiple lines"
+//	URL=postgres://${DB_HOST:-localhost}:${DB_PORT}/db
+//
+// Variables are interpolated from the keys defined above in the same file and then from lookup,
+// which may be nil. ${VAR:-default} uses default if VAR is unset or empty, ${VAR-default} only if unset.
+// Unquoted values are continued on the next line if they end with a backslash
+func ParseEnv(r io.Reader, lookup func(key string) (string, bool)) (map[string]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &envParser{
+		src:    string(data),
+		line:   1,
+		vars:   make(map[string]string),
+		lookup: lookup,
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.vars, nil
+}
+
+type envParser struct {
+	src    string
+	pos    int
+	line   int
+	vars   map[string]string
+	lookup func(key string) (string, bool)
+}
+
+func (p *envParser) parse() error {
+	for {
+		p.skipBlanks()
+		if p.eof() {
+			return nil
+		}
+
+		switch p.peek() {
+		case '\n':
+			p.next()
+			continue
+		case '#':
+			p.skipLine()
+			continue
+		}
+
+		if err := p.parseEntry(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *envParser) parseEntry() error {
+	if strings.HasPrefix(p.src[p.pos:], "export ") || strings.HasPrefix(p.src[p.pos:], "export\t") {
+		p.pos += len("export")
+		p.skipBlanks()
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune("= \t\r\n#", rune(p.peek())) {
+		p.next()
+	}
+	key := p.src[start:p.pos]
+	if !isValidEnvKey(key) {
+		return p.errorf("invalid key %q", key)
+	}
+
+	p.skipBlanks()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf("expected '=' after key %q", key)
+	}
+	p.next()
+	p.skipBlanks()
+
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case p.eof():
+	case p.peek() == '\'':
+		value, err = p.singleQuoted()
+	case p.peek() == '"':
+		value, err = p.doubleQuoted()
+	default:
+		value, err = p.unquoted()
+	}
+	if err != nil {
+		return err
+	}
+
+	p.skipBlanks()
+	if !p.eof() && p.peek() == '#' {
+		p.skipLine()
+	}
+	if !p.eof() && p.next() != '\n' {
+		return p.errorf("unexpected characters after value of %q", key)
+	}
+
+	p.vars[key] = value
+	return nil
+}
+
+func (p *envParser) singleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		p.next()
+	}
+	if p.eof() {
+		return "", &EnvSyntaxError{Line: startLine, Msg: "unterminated single-quoted value"}
+	}
+	value := p.src[start:p.pos]
+	p.next()
+	return value, nil
+}
+
+func (p *envParser) doubleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", &EnvSyntaxError{Line: startLine, Msg: "unterminated double-quoted value"}
+		}
+
+		switch c := p.next(); c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				continue
+			}
+			if p.atLineEnd(p.pos) {
+				// escaped newline joins lines
+				p.skipLineEnd()
+				continue
+			}
+			switch escaped := p.next(); escaped {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '"', '\\', '$', '\'':
+				b.WriteByte(escaped)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(escaped)
+			}
+		case '$':
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *envParser) unquoted() (string, error) {
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case p.atLineEnd(p.pos):
+			return strings.TrimRight(b.String(), " \t"), nil
+		case c == '#' && p.pos > 0 && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t'):
+			return strings.TrimRight(b.String(), " \t"), nil
+		case c == '\\' && p.pos+1 < len(p.src) && p.atLineEnd(p.pos+1):
+			p.next()
+			p.skipLineEnd()
+		case c == '$':
+			p.next()
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+	return strings.TrimRight(b.String(), " \t"), nil
+}
+
+// expand writes value of variable reference which starts right after '$'.
+// References may be nested in default values, e.g. ${A:-${B}}
+func (p *envParser) expand(b *strings.Builder) error {
+	if p.eof() {
+		b.WriteByte('$')
+		return nil
+	}
+
+	if p.peek() != '{' {
+		start := p.pos
+		for !p.eof() && isEnvKeyChar(p.peek(), p.pos == start) {
+			p.next()
+		}
+		if p.pos == start {
+			b.WriteByte('$')
+			return nil
+		}
+		value, _ := p.resolve(p.src[start:p.pos])
+		b.WriteString(value)
+		return nil
+	}
+
+	startLine := p.line
+	p.next()
+	start := p.pos
+	for !p.eof() && isEnvKeyChar(p.peek(), p.pos == start) {
+		p.next()
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return p.errorf("invalid variable reference")
+	}
+	value, found := p.resolve(name)
+
+	if p.eof() {
+		return &EnvSyntaxError{Line: startLine, Msg: "unterminated variable reference ${" + name}
+	}
+
+	switch {
+	case p.peek() == '}':
+		p.next()
+		b.WriteString(value)
+		return nil
+	case strings.HasPrefix(p.src[p.pos:], ":-"):
+		p.pos += 2
+		found = found && value != ""
+	case p.peek() == '-':
+		p.next()
+	default:
+		return p.errorf("invalid variable reference ${%s", name)
+	}
+
+	var def strings.Builder
+	for {
+		if p.eof() {
+			return &EnvSyntaxError{Line: startLine, Msg: "unterminated variable reference ${" + name}
+		}
+		c := p.next()
+		if c == '}' {
+			break
+		}
+		if c == '$' {
+			if err := p.expand(&def); err != nil {
+				return err
+			}
+			continue
+		}
+		def.WriteByte(c)
+	}
+
+	if found {
+		b.WriteString(value)
+	} else {
+		b.WriteString(def.String())
+	}
+	return nil
+}
+
+func (p *envParser) resolve(name string) (string, bool) {
+	if value, ok := p.vars[name]; ok {
+		return value, true
+	}
+	if p.lookup != nil {
+		return p.lookup(name)
+	}
+	return "", false
+}
+
+func (p *envParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *envParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *envParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *envParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.pos++
+	}
+}
+
+// atLineEnd reports whether the line ends at i, both "\n" and "\r\n" end a line
+// as well as a lone '\r' at the end of input
+func (p *envParser) atLineEnd(i int) bool {
+	switch {
+	case i >= len(p.src):
+		return false
+	case p.src[i] == '\n':
+		return true
+	case p.src[i] == '\r':
+		return i+1 == len(p.src) || p.src[i+1] == '\n'
+	}
+	return false
+}
+
+// skipLineEnd skips line ending found by atLineEnd
+func (p *envParser) skipLineEnd() {
+	if p.peek() == '\r' {
+		p.next()
+	}
+	if !p.eof() {
+		p.next()
+	}
+}
+
+func (p *envParser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func (p *envParser) errorf(format string, args ...interface{}) error {
+	return &EnvSyntaxError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isValidEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if !isEnvKeyChar(key[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvKeyChar(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnv(t *testing.T) {
+	content := `
+# a=b is a comment
+export EXPORTED=1
+  SPACED  =  value with spaces   # inline comment
+HASH=value#not-a-comment
+EMPTY=
+SINGLE='raw ${HOST} \n # kept'
+DOUBLE="tab\there \"quoted\" \$HOST"
+MULTI="first
+second"
+CONTINUED=one \
+two
+HOST_URL=http://${HOST}:${PORT:-8080}/$EXPORTED
+UNSET_DEFAULT=${MISSING-fallback}
+EMPTY_DEFAULT=${EMPTY:-${HOST}}
+EMPTY_KEPT=${EMPTY-fallback}
+`
+	lookup := func(key string) (string, bool) {
+		if key == "HOST" {
+			return "example.com", true
+		}
+		return "", false
+	}
+
+	vars, err := ParseEnv(strings.NewReader(content), lookup)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"EXPORTED":      "1",
+		"SPACED":        "value with spaces",
+		"HASH":          "value#not-a-comment",
+		"EMPTY":         "",
+		"SINGLE":        `raw ${HOST} \n # kept`,
+		"DOUBLE":        "tab\there \"quoted\" $HOST",
+		"MULTI":         "first\nsecond",
+		"CONTINUED":     "one two",
+		"HOST_URL":      "http://example.com:8080/1",
+		"UNSET_DEFAULT": "fallback",
+		"EMPTY_DEFAULT": "example.com",
+		"EMPTY_KEPT":    "",
+	}, vars)
+}
+
+func TestParseEnvCRLF(t *testing.T) {
+	content := "# comment\r\nA=1\r\nB = two words  \r\n\r\nC=\"quoted\"\r\nD='single' # comment\r\nE=one \\\r\ntwo\r\nF=\"x \\\r\ny\"\r\nEMPTY=\r\nLAST=end\r"
+
+	vars, err := ParseEnv(strings.NewReader(content), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"A":     "1",
+		"B":     "two words",
+		"C":     "quoted",
+		"D":     "single",
+		"E":     "one two",
+		"F":     "x y",
+		"EMPTY": "",
+		"LAST":  "end",
+	}, vars)
+}
+
+func TestParseEnvCRLFErrors(t *testing.T) {
+	_, err := ParseEnv(strings.NewReader("A=1\r\nKEY\r\n"), nil)
+	var syntaxErr *EnvSyntaxError
+	require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+	assert.Equal(t, 2, syntaxErr.Line)
+	assert.Equal(t, `expected '=' after key "KEY"`, syntaxErr.Msg)
+
+	_, err = ParseEnv(strings.NewReader("A='x' y\r\n"), nil)
+	require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+	assert.Equal(t, 1, syntaxErr.Line)
+}
+
+func TestParseEnvErrors(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		line    int
+	}{
+		"missing equals":      {"A=1\nB\n", 2},
+		"invalid key":         {"A=1\n\n1A=2\n", 3},
+		"unterminated quote":  {"A=1\nB=\"open\nstill open\n", 2},
+		"trailing garbage":    {"A='x' y\n", 1},
+		"unterminated ref":    {"A=${B\n", 1},
+		"invalid ref":         {"A=${}\n", 1},
+		"multiline then fail": {"A=\"x\ny\"\nB C\n", 3},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseEnv(strings.NewReader(c.content), nil)
+			var syntaxErr *EnvSyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+			assert.Equal(t, c.line, syntaxErr.Line)
+		})
+	}
+}
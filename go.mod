@@ -4,9 +4,10 @@ go 1.13
 
 require (
 	github.com/facebookgo/stack v0.0.0-20160209184415-751773369052
-	github.com/go-openapi/runtime v0.19.10 // indirect
+	github.com/go-openapi/runtime v0.19.10
 	github.com/google/uuid v1.1.1
 	github.com/rs/xid v1.2.1
 	github.com/sirupsen/logrus v1.4.2
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.4
 )
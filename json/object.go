@@ -6,18 +6,41 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/itimofeev/go-util/cast"
 )
 
 const (
-	keySep            = "."
-	flatSep           = "_"
-	quotedFlatSep     = "__"
-	defaultTimeFormat = "2006-01-02T15:04:05.999999999"
+	keySep        = "."
+	flatSep       = "_"
+	quotedFlatSep = "__"
 )
 
+var (
+	defaultCaster = cast.NewCaster()
+	// caster holds *cast.Caster set by SetCaster
+	caster atomic.Value
+)
+
+// SetCaster replaces the caster used by Object accessors to convert field values, nil restores the default one.
+// It is safe to call concurrently with the accessors
+func SetCaster(c *cast.Caster) {
+	if c == nil {
+		c = defaultCaster
+	}
+	caster.Store(c)
+}
+
+func currentCaster() *cast.Caster {
+	if c, ok := caster.Load().(*cast.Caster); ok {
+		return c
+	}
+	return defaultCaster
+}
+
 type Object map[string]interface{}
 
 func NewObject() Object {
@@ -33,7 +56,7 @@ func (jo Object) PutField(key string, val interface{}) interface{} {
 }
 
 func (jo Object) GetFieldAsString(key string) string {
-	casted, err := cast.TryString(jo.GetField(key))
+	casted, err := currentCaster().TryString(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -41,30 +64,61 @@ func (jo Object) GetFieldAsString(key string) string {
 }
 
 func (jo Object) GetFieldAsInt(key string) int {
-	casted, err := cast.TryInt64(jo.GetField(key))
+	casted, err := currentCaster().TryInt64(jo.GetField(key))
 	if err == nil {
 		return int(casted)
 	}
 	return 0
 }
 
+func (jo Object) GetFieldAsBool(key string) bool {
+	casted, err := currentCaster().TryBool(jo.GetField(key))
+	if err == nil {
+		return casted
+	}
+	return false
+}
+
+func (jo Object) GetFieldAsDuration(key string, opts ...cast.Option) time.Duration {
+	casted, err := currentCaster().TryDuration(jo.GetField(key), opts...)
+	if err == nil {
+		return casted
+	}
+	return 0
+}
+
+func (jo Object) GetFieldAsByteSize(key string) uint64 {
+	casted, err := currentCaster().TryByteSize(jo.GetField(key))
+	if err == nil {
+		return casted
+	}
+	return 0
+}
+
+// GetFieldAsTime returns field parsed with the currentCaster().TryDateTime layout chain. If formats are passed
+// only string fields matching one of them and time.Time fields are accepted
 func (jo Object) GetFieldAsTime(key string, format ...string) *time.Time {
-	val := jo.GetField(key)
-	if val != nil {
-		switch fieldVal := val.(type) {
-		case string:
-			timeFormat := defaultTimeFormat
-			if len(format) > 0 {
-				timeFormat = format[0]
-			}
-			parsed, err := time.Parse(timeFormat, fieldVal)
-			if err != nil {
-				return nil
+	if len(format) > 0 {
+		return parseTimeFormats(jo.GetField(key), format)
+	}
+
+	casted, err := currentCaster().TryDateTime(jo.GetField(key))
+	if err == nil {
+		return &casted
+	}
+	return nil
+}
+
+func parseTimeFormats(val interface{}, formats []string) *time.Time {
+	switch fieldVal := val.(type) {
+	case string:
+		for _, format := range formats {
+			if parsed, err := time.Parse(format, fieldVal); err == nil {
+				return &parsed
 			}
-			return &parsed
-		case time.Time:
-			return &fieldVal
 		}
+	case time.Time:
+		return &fieldVal
 	}
 	return nil
 }
@@ -83,15 +137,23 @@ func (jo Object) GetFieldAsObject(key string) Object {
 }
 
 func (jo Object) GetFieldAsUUID(key string) string {
-	casted, err := cast.TryUUID(jo.GetField(key))
+	casted, err := currentCaster().TryUUID(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
 	return ""
 }
 
+func (jo Object) GetFieldAsUUIDValue(key string) uuid.UUID {
+	casted, err := currentCaster().TryUUIDValue(jo.GetField(key))
+	if err == nil {
+		return casted
+	}
+	return uuid.Nil
+}
+
 func (jo Object) GetFieldAsInt8(key string) int8 {
-	casted, err := cast.TryInt8(jo.GetField(key))
+	casted, err := currentCaster().TryInt8(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -99,7 +161,7 @@ func (jo Object) GetFieldAsInt8(key string) int8 {
 }
 
 func (jo Object) GetFieldAsInt16(key string) int16 {
-	casted, err := cast.TryInt16(jo.GetField(key))
+	casted, err := currentCaster().TryInt16(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -107,7 +169,7 @@ func (jo Object) GetFieldAsInt16(key string) int16 {
 }
 
 func (jo Object) GetFieldAsInt32(key string) int32 {
-	casted, err := cast.TryInt32(jo.GetField(key))
+	casted, err := currentCaster().TryInt32(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -115,7 +177,7 @@ func (jo Object) GetFieldAsInt32(key string) int32 {
 }
 
 func (jo Object) GetFieldAsInt64(key string) int64 {
-	casted, err := cast.TryInt64(jo.GetField(key))
+	casted, err := currentCaster().TryInt64(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -123,7 +185,7 @@ func (jo Object) GetFieldAsInt64(key string) int64 {
 }
 
 func (jo Object) GetFieldAsUint8(key string) uint8 {
-	casted, err := cast.TryUInt8(jo.GetField(key))
+	casted, err := currentCaster().TryUInt8(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -131,7 +193,7 @@ func (jo Object) GetFieldAsUint8(key string) uint8 {
 }
 
 func (jo Object) GetFieldAsUint16(key string) uint16 {
-	casted, err := cast.TryUInt16(jo.GetField(key))
+	casted, err := currentCaster().TryUInt16(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -139,7 +201,7 @@ func (jo Object) GetFieldAsUint16(key string) uint16 {
 }
 
 func (jo Object) GetFieldAsUint32(key string) uint32 {
-	casted, err := cast.TryUInt32(jo.GetField(key))
+	casted, err := currentCaster().TryUInt32(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -147,7 +209,7 @@ func (jo Object) GetFieldAsUint32(key string) uint32 {
 }
 
 func (jo Object) GetFieldAsUint64(key string) uint64 {
-	casted, err := cast.TryUInt64(jo.GetField(key))
+	casted, err := currentCaster().TryUInt64(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -155,7 +217,7 @@ func (jo Object) GetFieldAsUint64(key string) uint64 {
 }
 
 func (jo Object) GetFieldAsFloat32(key string) float32 {
-	casted, err := cast.TryFloat32(jo.GetField(key))
+	casted, err := currentCaster().TryFloat32(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -163,7 +225,7 @@ func (jo Object) GetFieldAsFloat32(key string) float32 {
 }
 
 func (jo Object) GetFieldAsFloat64(key string) float64 {
-	casted, err := cast.TryFloat64(jo.GetField(key))
+	casted, err := currentCaster().TryFloat64(jo.GetField(key))
 	if err == nil {
 		return casted
 	}
@@ -171,60 +233,76 @@ func (jo Object) GetFieldAsFloat64(key string) float64 {
 }
 
 func (jo Object) MustGetFieldAsString(key string) (string, error) {
-	return cast.TryString(jo.GetField(key))
+	return currentCaster().TryString(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsInt(key string) (int, error) {
-	field, err := cast.TryInt64(jo.GetField(key))
+	field, err := currentCaster().TryInt64(jo.GetField(key))
 	return int(field), err
 }
 
-func (jo Object) MustGetFieldAsTime(key string) (time.Time, error) {
-	return cast.TryDateTime(jo.GetField(key))
+func (jo Object) MustGetFieldAsBool(key string) (bool, error) {
+	return currentCaster().TryBool(jo.GetField(key))
+}
+
+func (jo Object) MustGetFieldAsDuration(key string, opts ...cast.Option) (time.Duration, error) {
+	return currentCaster().TryDuration(jo.GetField(key), opts...)
+}
+
+func (jo Object) MustGetFieldAsByteSize(key string) (uint64, error) {
+	return currentCaster().TryByteSize(jo.GetField(key))
+}
+
+func (jo Object) MustGetFieldAsTime(key string, opts ...cast.Option) (time.Time, error) {
+	return currentCaster().TryDateTime(jo.GetField(key), opts...)
+}
+
+func (jo Object) MustGetFieldAsUUID(key string, opts ...cast.Option) (string, error) {
+	return currentCaster().TryUUID(jo.GetField(key), opts...)
 }
 
-func (jo Object) MustGetFieldAsUUID(key string) (string, error) {
-	return cast.TryUUID(jo.GetField(key))
+func (jo Object) MustGetFieldAsUUIDValue(key string, opts ...cast.Option) (uuid.UUID, error) {
+	return currentCaster().TryUUIDValue(jo.GetField(key), opts...)
 }
 
 func (jo Object) MustGetFieldAsInt8(key string) (int8, error) {
-	return cast.TryInt8(jo.GetField(key))
+	return currentCaster().TryInt8(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsInt16(key string) (int16, error) {
-	return cast.TryInt16(jo.GetField(key))
+	return currentCaster().TryInt16(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsInt32(key string) (int32, error) {
-	return cast.TryInt32(jo.GetField(key))
+	return currentCaster().TryInt32(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsInt64(key string) (int64, error) {
-	return cast.TryInt64(jo.GetField(key))
+	return currentCaster().TryInt64(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsUint8(key string) (uint8, error) {
-	return cast.TryUInt8(jo.GetField(key))
+	return currentCaster().TryUInt8(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsUint16(key string) (uint16, error) {
-	return cast.TryUInt16(jo.GetField(key))
+	return currentCaster().TryUInt16(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsUint32(key string) (uint32, error) {
-	return cast.TryUInt32(jo.GetField(key))
+	return currentCaster().TryUInt32(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsUint64(key string) (uint64, error) {
-	return cast.TryUInt64(jo.GetField(key))
+	return currentCaster().TryUInt64(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsFloat32(key string) (float32, error) {
-	return cast.TryFloat32(jo.GetField(key))
+	return currentCaster().TryFloat32(jo.GetField(key))
 }
 
 func (jo Object) MustGetFieldAsFloat64(key string) (float64, error) {
-	return cast.TryFloat64(jo.GetField(key))
+	return currentCaster().TryFloat64(jo.GetField(key))
 }
 
 func (jo Object) Remove(key string) {
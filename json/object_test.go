@@ -2,8 +2,12 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/itimofeev/go-util/cast"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,3 +49,102 @@ func TestGetValueForField(t *testing.T) {
 	require.Equal(t, int64(10), obj.GetFieldAsInt64("test"))
 	require.Equal(t, int64(0), obj.GetFieldAsInt64("unknown field"))
 }
+
+func TestGetFieldAsBool(t *testing.T) {
+	obj := Object{"flag": 1, "enabled": "yes", "off": "false", "bad": "maybe"}
+	require.True(t, obj.GetFieldAsBool("flag"))
+	require.True(t, obj.GetFieldAsBool("enabled"))
+	require.False(t, obj.GetFieldAsBool("off"))
+	require.False(t, obj.GetFieldAsBool("bad"))
+	require.False(t, obj.GetFieldAsBool("unknown field"))
+
+	_, err := obj.MustGetFieldAsBool("bad")
+	require.Error(t, err)
+}
+
+func TestGetFieldAsDuration(t *testing.T) {
+	obj := Object{"timeout": "PT5M", "interval": 30, "delay": 250, "bad": "soon"}
+	require.Equal(t, 5*time.Minute, obj.GetFieldAsDuration("timeout"))
+	require.Equal(t, 30*time.Second, obj.GetFieldAsDuration("interval"))
+	require.Equal(t, 250*time.Millisecond, obj.GetFieldAsDuration("delay", cast.WithDurationUnit(time.Millisecond)))
+	require.Equal(t, time.Duration(0), obj.GetFieldAsDuration("bad"))
+
+	_, err := obj.MustGetFieldAsDuration("bad")
+	require.Error(t, err)
+}
+
+func TestGetFieldAsByteSize(t *testing.T) {
+	obj := Object{"limit": "10MiB", "bad": "10 apples"}
+	require.Equal(t, uint64(10<<20), obj.GetFieldAsByteSize("limit"))
+	require.Equal(t, uint64(0), obj.GetFieldAsByteSize("bad"))
+
+	_, err := obj.MustGetFieldAsByteSize("bad")
+	require.Error(t, err)
+}
+
+func TestGetFieldAsTime(t *testing.T) {
+	obj := Object{
+		"time":  Object{"datetime": "2019-03-27T11:10:14.920000", "utc": "2019-03-27T08:10:14.92Z"},
+		"epoch": 1553674214920,
+		"ru":    "27.03.2019",
+		"bad":   "yesterday",
+	}
+	expected := time.Date(2019, 3, 27, 8, 10, 14, 920000000, time.UTC)
+	require.Equal(t, time.Date(2019, 3, 27, 11, 10, 14, 920000000, time.UTC), *obj.GetFieldAsTime("time.datetime"))
+	require.True(t, expected.Equal(*obj.GetFieldAsTime("time.utc")))
+	require.True(t, expected.Equal(*obj.GetFieldAsTime("epoch")))
+	require.Equal(t, time.Date(2019, 3, 27, 0, 0, 0, 0, time.UTC), *obj.GetFieldAsTime("ru", "02.01.2006"))
+	require.Nil(t, obj.GetFieldAsTime("bad"))
+	require.Nil(t, obj.GetFieldAsTime("epoch", "02.01.2006"))
+	require.Nil(t, obj.GetFieldAsTime("time.utc", "02.01.2006"))
+	require.Nil(t, obj.GetFieldAsTime("unknown field"))
+
+	msk := time.FixedZone("MSK", 3*60*60)
+	parsed, err := obj.MustGetFieldAsTime("time.datetime", cast.WithLocation(msk))
+	require.NoError(t, err)
+	require.True(t, expected.Equal(parsed))
+}
+
+func TestCaster(t *testing.T) {
+	defer SetCaster(nil)
+
+	obj := Object{"count": 3.9}
+	require.Equal(t, int64(3), obj.GetFieldAsInt64("count"))
+
+	SetCaster(cast.NewCaster(cast.WithPolicy(cast.PolicyRound)))
+	require.Equal(t, int64(4), obj.GetFieldAsInt64("count"))
+
+	SetCaster(cast.NewCaster(cast.WithPolicy(cast.PolicyStrict)))
+	require.Equal(t, int64(0), obj.GetFieldAsInt64("count"))
+	_, err := obj.MustGetFieldAsInt64("count")
+	require.True(t, errors.Is(err, cast.ErrPrecisionLoss))
+}
+
+func TestSetCasterConcurrently(t *testing.T) {
+	defer SetCaster(nil)
+
+	obj := Object{"count": 3.9}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetCaster(cast.NewCaster(cast.WithPolicy(cast.PolicyRound)))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		n := obj.GetFieldAsInt64("count")
+		require.True(t, n == 3 || n == 4)
+	}
+	<-done
+}
+
+func TestGetFieldAsUUID(t *testing.T) {
+	obj := Object{"id": "{0D49659F-1EDC-49F2-872A-5EAD1DB8390A}", "bad": "0d49659f"}
+	require.Equal(t, "0d49659f-1edc-49f2-872a-5ead1db8390a", obj.GetFieldAsUUID("id"))
+	require.Equal(t, uuid.MustParse("0d49659f-1edc-49f2-872a-5ead1db8390a"), obj.GetFieldAsUUIDValue("id"))
+	require.Equal(t, "", obj.GetFieldAsUUID("bad"))
+	require.Equal(t, uuid.Nil, obj.GetFieldAsUUIDValue("bad"))
+
+	_, err := obj.MustGetFieldAsUUIDValue("id", cast.WithUUIDVersion(1))
+	require.Error(t, err)
+}
@@ -0,0 +1,295 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Exit codes returned by ExitCode
+const (
+	ExitOK              = 0
+	ExitFailure         = 1
+	ExitShutdownFailure = 2
+)
+
+// HealthState is the state of App
+type HealthState int32
+
+// States App passes through, Failed replaces Stopped if start or run failed
+const (
+	StateCreated HealthState = iota
+	StateStarting
+	StateRunning
+	StateStopping
+	StateStopped
+	StateFailed
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case StateCreated:
+		return "created"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("HealthState(%d)", int32(s))
+	}
+}
+
+// Component is a part of App started in the order of adding and stopped in reverse order.
+// Start must not block, long running work is started in background and reports failures with App.Fail
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+	// StopTimeout limits Stop, App.StopTimeout is used if it is zero
+	StopTimeout time.Duration
+}
+
+// Lifecycle phases of LifecycleError
+const (
+	PhaseStart = "start"
+	PhaseRun   = "run"
+	PhaseStop  = "stop"
+)
+
+// LifecycleError describes failure of the component in the phase
+type LifecycleError struct {
+	Phase     string
+	Component string
+	Err       error
+}
+
+func (e *LifecycleError) Error() string {
+	if e.Component == "" {
+		return fmt.Sprintf("%s: %s", e.Phase, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Phase, e.Component, e.Err)
+}
+
+func (e *LifecycleError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns process exit code for the error returned by App.Run
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var lifecycleErr *LifecycleError
+	if errors.As(err, &lifecycleErr) && lifecycleErr.Phase == PhaseStop {
+		return ExitShutdownFailure
+	}
+	return ExitFailure
+}
+
+// App runs components until it is stopped by signal, context or failure, e.g.
+//
+//	app := util.NewApp("arp server", version, util.Log)
+//	app.Add(util.Component{Name: "http", Start: startServer, Stop: server.Shutdown})
+//	app.Main()
+type App struct {
+	// StopTimeout limits Stop of every component, 10 seconds by default
+	StopTimeout time.Duration
+	// Signals start graceful shutdown, SIGINT and SIGTERM by default
+	Signals []os.Signal
+
+	log        *logrus.Entry
+	components []Component
+	state      int32
+	failures   chan error
+}
+
+// NewApp returns app logging its lifecycle to log with name and version
+func NewApp(name string, version interface{}, log logrus.FieldLogger) *App {
+	return &App{
+		StopTimeout: 10 * time.Second,
+		Signals:     []os.Signal{os.Interrupt, syscall.SIGTERM},
+		log:         log.WithFields(logrus.Fields{"version": version, "app": name}),
+		failures:    make(chan error, 1),
+	}
+}
+
+// Add adds component started after the already added ones
+func (a *App) Add(c Component) {
+	a.components = append(a.components, c)
+}
+
+// State returns the current state
+func (a *App) State() HealthState {
+	return HealthState(atomic.LoadInt32(&a.state))
+}
+
+func (a *App) setState(s HealthState) {
+	atomic.StoreInt32(&a.state, int32(s))
+}
+
+// Fail starts shutdown because of err, Run returns it as LifecycleError of the run phase
+func (a *App) Fail(err error) {
+	select {
+	case a.failures <- err:
+	default:
+	}
+}
+
+// HealthHandler returns handler responding with the state in json, status is 200 only while app is running
+func (a *App) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := a.State()
+		w.Header().Set(contentType, applicationJSONCharsetUTF8)
+		if state != StateRunning {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"state": state.String()})
+	})
+}
+
+// Main runs app and exits the process with ExitCode of the result
+func (a *App) Main() {
+	os.Exit(ExitCode(a.Run(context.Background())))
+}
+
+// Run starts components in order, waits for a signal, ctx to be done or Fail and stops
+// the started components in reverse order. Log is flushed before it returns
+func (a *App) Run(ctx context.Context) error {
+	started := time.Now()
+	a.setState(StateStarting)
+	a.log.Info("application starting")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	if len(a.Signals) > 0 {
+		signal.Notify(signals, a.Signals...)
+		defer signal.Stop(signals)
+	}
+
+	var runErr error
+	running := 0
+	for _, c := range a.components {
+		took := time.Now()
+		if err := callComponent(ctx, c.Start); err != nil {
+			runErr = &LifecycleError{Phase: PhaseStart, Component: c.Name, Err: err}
+			a.log.WithError(err).WithField("component", c.Name).Error("component failed to start")
+			break
+		}
+		running++
+		a.log.WithFields(logrus.Fields{"component": c.Name, "took": time.Since(took)}).Info("component started")
+	}
+
+	if runErr == nil {
+		a.setState(StateRunning)
+		a.log.WithField("took", time.Since(started)).Info("application started")
+
+		select {
+		case <-ctx.Done():
+			a.log.Info("shutting down, context is done")
+		case sig := <-signals:
+			a.log.WithField("signal", sig.String()).Info("shutting down on signal")
+		case err := <-a.failures:
+			runErr = &LifecycleError{Phase: PhaseRun, Err: err}
+			a.log.WithError(err).Error("shutting down on failure")
+		}
+	}
+
+	a.setState(StateStopping)
+	stopErr := a.stop(running)
+
+	err := runErr
+	if err == nil {
+		err = stopErr
+	}
+
+	entry := a.log.WithFields(logrus.Fields{"uptime": time.Since(started), "exitCode": ExitCode(err)})
+	if err != nil {
+		a.setState(StateFailed)
+		entry.WithError(err).Error("exited with error")
+	} else {
+		a.setState(StateStopped)
+		entry.Info("exit with ok")
+	}
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), a.StopTimeout)
+	defer cancelFlush()
+	_ = flushLogger(flushCtx, a.log)
+	return err
+}
+
+// stop stops first n components in reverse order and returns the first error
+func (a *App) stop(n int) error {
+	var firstErr error
+	for i := n - 1; i >= 0; i-- {
+		c := a.components[i]
+		if c.Stop == nil {
+			continue
+		}
+
+		timeout := c.StopTimeout
+		if timeout <= 0 {
+			timeout = a.StopTimeout
+		}
+
+		took := time.Now()
+		err := stopComponent(c.Stop, timeout)
+		entry := a.log.WithFields(logrus.Fields{"component": c.Name, "took": time.Since(took)})
+		if err != nil {
+			entry.WithError(err).Error("component failed to stop")
+			if firstErr == nil {
+				firstErr = &LifecycleError{Phase: PhaseStop, Component: c.Name, Err: err}
+			}
+			continue
+		}
+		entry.Info("component stopped")
+	}
+	return firstErr
+}
+
+// stopComponent calls stop with timeout and doesn't wait for it longer even if stop ignores the context
+func stopComponent(stop func(ctx context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- callComponent(ctx, stop)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stop timed out after %s", timeout)
+	}
+}
+
+// callComponent calls fn converting panic to error
+func callComponent(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if fn == nil {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
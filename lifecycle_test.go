@@ -0,0 +1,139 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(out *bytes.Buffer) *App {
+	logger := &logrus.Logger{Out: out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+	app := NewApp("test", "1.0", logger)
+	app.Signals = nil
+	app.StopTimeout = 50 * time.Millisecond
+	return app
+}
+
+func recordingComponent(name string, calls *[]string, startErr error) Component {
+	return Component{
+		Name: name,
+		Start: func(context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return startErr
+		},
+		Stop: func(context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestAppRun(t *testing.T) {
+	var out bytes.Buffer
+	var calls []string
+	app := newTestApp(&out)
+	app.Add(recordingComponent("db", &calls, nil))
+	app.Add(recordingComponent("http", &calls, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- app.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return app.State() == StateRunning
+	}, time.Second, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	app.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"state": "running"}`, rec.Body.String())
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"start db", "start http", "stop http", "stop db"}, calls)
+	assert.Equal(t, StateStopped, app.State())
+	assert.Contains(t, out.String(), `"msg":"exit with ok"`)
+	assert.Contains(t, out.String(), `"uptime"`)
+
+	rec = httptest.NewRecorder()
+	app.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestAppRunFlushesLogger(t *testing.T) {
+	out := &flushRecorder{}
+	logger := &logrus.Logger{Out: out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+	app := NewApp("test", "1.0", logger)
+	app.Signals = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, app.Run(ctx))
+	assert.Contains(t, string(out.flushed), `"msg":"exit with ok"`)
+}
+
+func TestAppStartFailure(t *testing.T) {
+	var out bytes.Buffer
+	var calls []string
+	app := newTestApp(&out)
+	app.Add(recordingComponent("db", &calls, nil))
+	app.Add(recordingComponent("http", &calls, errors.New("address in use")))
+	app.Add(recordingComponent("worker", &calls, nil))
+
+	err := app.Run(context.Background())
+
+	var lifecycleErr *LifecycleError
+	require.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, PhaseStart, lifecycleErr.Phase)
+	assert.Equal(t, "http", lifecycleErr.Component)
+	assert.Equal(t, ExitFailure, ExitCode(err))
+	assert.Equal(t, []string{"start db", "start http", "stop db"}, calls)
+	assert.Equal(t, StateFailed, app.State())
+	assert.Contains(t, out.String(), `"msg":"exited with error"`)
+}
+
+func TestAppFailAndStopTimeout(t *testing.T) {
+	var out bytes.Buffer
+	app := newTestApp(&out)
+	app.Add(Component{
+		Name: "stuck",
+		Start: func(context.Context) error {
+			return nil
+		},
+		Stop: func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+	app.Add(Component{
+		Name: "panicking",
+		Start: func(context.Context) error {
+			app.Fail(errors.New("worker died"))
+			return nil
+		},
+		Stop: func(context.Context) error {
+			panic("boom")
+		},
+	})
+
+	err := app.Run(context.Background())
+
+	var lifecycleErr *LifecycleError
+	require.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, PhaseRun, lifecycleErr.Phase)
+	assert.Contains(t, out.String(), `"component":"panicking","error":"panic: boom"`)
+	assert.Contains(t, out.String(), `"component":"stuck","error":"stop timed out after 50ms"`)
+
+	assert.Equal(t, ExitShutdownFailure, ExitCode(&LifecycleError{Phase: PhaseStop, Err: errors.New("timeout")}))
+	assert.Equal(t, ExitOK, ExitCode(nil))
+}
@@ -1,20 +1,25 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"os"
+	"sync"
 	"time"
 )
 
 const LogTimestampFormat = "2006-01-02T15:04:05.999"
 
-// Log some common log for tests
+// Log is the common logger used by this package, it writes json at debug level to stdout
+// unless configured with ConfigureLog or SetLog
 var Log = &logrus.Logger{
 	Level:     logrus.DebugLevel,
 	Out:       os.Stdout,
 	Formatter: &logrus.JSONFormatter{TimestampFormat: LogTimestampFormat},
+	Hooks:     make(logrus.LevelHooks),
+	ExitFunc:  os.Exit,
 }
 
 func LogWithError(log logrus.FieldLogger, err error, msg string) {
@@ -25,7 +30,8 @@ func LogWithError(log logrus.FieldLogger, err error, msg string) {
 	log.Debug(msg)
 }
 
-// LogStartStop logs start and finish of application
+// LogStartStop logs start and finish of application and flushes the log output,
+// see App for the complete lifecycle management
 // correct way to call is the following:
 //
 // defer util.LogStartStop(config.Log, config.Version, "arp server") ()
@@ -40,12 +46,80 @@ func LogStartStop(log logrus.FieldLogger, version interface{}, appName string) f
 		err := recover()
 		if err != nil {
 			entry.WithField("err", err).Error("exited with error")
-			panic(err)
 		} else {
 			entry.Debug("exit with ok")
 		}
-		time.Sleep(time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), logFlushTimeout)
+		_ = flushLogger(ctx, log)
+		cancel()
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+const logFlushTimeout = 5 * time.Second
+
+// flushingExit returns ExitFunc for log which flushes its output before calling exit,
+// so entries logged with Fatal aren't lost in the AsyncWriter queue
+func flushingExit(log logrus.FieldLogger, exit func(code int)) func(code int) {
+	return func(code int) {
+		ctx, cancel := context.WithTimeout(context.Background(), logFlushTimeout)
+		_ = flushLogger(ctx, log)
+		cancel()
+		exit(code)
+	}
+}
+
+var logExitHandlerOnce sync.Once
+
+// registerLogExitHandler makes Fatal of any logger flush the output of Log, e.g. in GetJSON
+func registerLogExitHandler() {
+	logExitHandlerOnce.Do(func() {
+		logrus.RegisterExitHandler(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), logFlushTimeout)
+			defer cancel()
+			_ = FlushLog(ctx)
+		})
+	})
+}
+
+// FlushLog writes out entries buffered by the output of Log and syncs it if it is a file
+func FlushLog(ctx context.Context) error {
+	return flushLogger(ctx, Log)
+}
+
+func flushLogger(ctx context.Context, log logrus.FieldLogger) error {
+	var logger *logrus.Logger
+	switch l := log.(type) {
+	case *logrus.Logger:
+		logger = l
+	case *logrus.Entry:
+		logger = l.Logger
+	default:
+		logger = Log
+	}
+
+	logMu.Lock()
+	out := logger.Out
+	logMu.Unlock()
+
+	switch w := out.(type) {
+	case interface{ Flush(context.Context) error }:
+		return w.Flush(ctx)
+	case *os.File:
+		if w == os.Stdout || w == os.Stderr {
+			// syncing of terminals and pipes isn't supported on every platform
+			_ = w.Sync()
+			return nil
+		}
+		return w.Sync()
+	case interface{ Sync() error }:
+		return w.Sync()
 	}
+	return nil
 }
 
 // GetJSON returns json by input object
@@ -0,0 +1,208 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy tells AsyncWriter what to do with lines written when its queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowDrop drops the line and counts it in AsyncWriterStats.Dropped
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock waits for the free space in the queue
+	OverflowBlock
+)
+
+// AsyncWriterOptions configures NewAsyncWriter
+type AsyncWriterOptions struct {
+	// QueueSize is the number of lines queued for writing, 1024 by default
+	QueueSize int
+	// Policy is applied when the queue is full
+	Policy OverflowPolicy
+	// BufferSize is the size of the buffer lines are written through, 64KiB by default
+	BufferSize int
+	// FlushInterval is the period of flushing the buffer, 1 second by default
+	FlushInterval time.Duration
+}
+
+// AsyncWriterStats are counters of AsyncWriter
+type AsyncWriterStats struct {
+	Written uint64
+	Dropped uint64
+	Queued  int
+}
+
+// AsyncWriter is the io.Writer for loggers which queues lines and writes them to the underlying writer
+// from the background goroutine, so logging doesn't wait for slow output
+type AsyncWriter struct {
+	out     io.Writer
+	policy  OverflowPolicy
+	queue   chan []byte
+	flushes chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+
+	written uint64
+	dropped uint64
+
+	// mu guards closed, writes hold it for reading while they queue lines,
+	// so no line is queued after Close lets the background goroutine drain the queue
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncWriter returns writer to out, call Close to flush and stop it
+func NewAsyncWriter(out io.Writer, opts AsyncWriterOptions) *AsyncWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 64 << 10
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+
+	w := &AsyncWriter{
+		out:     out,
+		policy:  opts.Policy,
+		queue:   make(chan []byte, opts.QueueSize),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run(bufio.NewWriterSize(out, opts.BufferSize), opts.FlushInterval)
+	return w
+}
+
+// Write queues copy of p, it never returns error for the dropped line.
+// Empty writes, e.g. of entries suppressed by Sampler.Formatter, are ignored
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	line := append([]byte(nil), p...)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.policy == OverflowBlock {
+		w.queue <- line
+		return len(p), nil
+	}
+
+	select {
+	case w.queue <- line:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Flush writes out lines queued before the call, it returns the last write error of the underlying writer
+// since the previous Flush
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case w.flushes <- reply:
+	case <-w.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes queued lines and stops the writer, the underlying writer isn't closed
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mu.Unlock()
+	<-w.stopped
+	return nil
+}
+
+// Stats returns counters of written and dropped lines and the current length of the queue
+func (w *AsyncWriter) Stats() AsyncWriterStats {
+	return AsyncWriterStats{
+		Written: atomic.LoadUint64(&w.written),
+		Dropped: atomic.LoadUint64(&w.dropped),
+		Queued:  len(w.queue),
+	}
+}
+
+func (w *AsyncWriter) run(buf *bufio.Writer, flushInterval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	// lastErr is reported by the next Flush, the buffer is reset after errors so later lines are retried
+	var lastErr error
+	fail := func(err error) {
+		lastErr = err
+		buf.Reset(w.out)
+	}
+	write := func(line []byte) {
+		if _, err := buf.Write(line); err != nil {
+			fail(err)
+			return
+		}
+		atomic.AddUint64(&w.written, 1)
+	}
+	flush := func() {
+		if err := buf.Flush(); err != nil {
+			fail(err)
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case line := <-w.queue:
+				write(line)
+			default:
+				flush()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case line := <-w.queue:
+			write(line)
+		case reply := <-w.flushes:
+			// lines queued after the request are left for the loop, so Flush returns under steady load
+			for n := len(w.queue); n > 0; n-- {
+				write(<-w.queue)
+			}
+			flush()
+			reply <- lastErr
+			lastErr = nil
+		case <-ticker.C:
+			flush()
+		case <-w.done:
+			drain()
+			return
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter blocks writes until the gate is opened
+type gatedWriter struct {
+	gate chan struct{}
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriterFlush(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	close(out.gate)
+	w := NewAsyncWriter(out, AsyncWriterOptions{FlushInterval: time.Hour})
+
+	line := []byte("first\n")
+	_, err := w.Write(line)
+	require.NoError(t, err)
+	copy(line, "reused")
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Flush(context.Background()))
+	assert.Equal(t, "first\nsecond\n", out.String())
+	assert.Equal(t, AsyncWriterStats{Written: 2}, w.Stats())
+
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("closed\n"))
+	assert.Error(t, err)
+	assert.NoError(t, w.Flush(context.Background()))
+}
+
+func TestAsyncWriterDrop(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	w := NewAsyncWriter(out, AsyncWriterOptions{QueueSize: 2, BufferSize: 1})
+
+	for i := 0; i < 10; i++ {
+		_, err := fmt.Fprintf(w, "line %d\n", i)
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, w.Flush(ctx))
+
+	close(out.gate)
+	require.NoError(t, w.Close())
+
+	stats := w.Stats()
+	assert.Equal(t, uint64(10), stats.Written+stats.Dropped)
+	assert.True(t, stats.Dropped >= 7, "dropped %d", stats.Dropped)
+	assert.Contains(t, out.String(), "line 0\n")
+}
+
+func TestAsyncWriterBlock(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	w := NewAsyncWriter(out, AsyncWriterOptions{QueueSize: 1, BufferSize: 1, Policy: OverflowBlock})
+
+	written := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("writes must block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(out.gate)
+	<-written
+	require.NoError(t, w.Close())
+	assert.Equal(t, "line 0\nline 1\nline 2\nline 3\nline 4\n", out.String())
+	assert.Equal(t, uint64(0), w.Stats().Dropped)
+}
+
+func TestLogStartStopFlushesAsyncLog(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	close(out.gate)
+	logger, err := NewLogger(LoggerOptions{Output: out, Async: true, AsyncQueueSize: 16})
+	require.NoError(t, err)
+	defer logger.Out.(*AsyncWriter).Close()
+
+	func() {
+		defer LogStartStop(logger, "1.0", "test")()
+		logger.Info("working")
+	}()
+
+	assert.Contains(t, out.String(), "working")
+	assert.Contains(t, out.String(), "exit with ok")
+}
+
+// failingWriter fails the first write and then writes to buf
+type failingWriter struct {
+	failed bool
+	buf    bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		w.failed = true
+		return 0, fmt.Errorf("disk full")
+	}
+	return w.buf.Write(p)
+}
+
+func TestAsyncWriterFlushResetsError(t *testing.T) {
+	out := &failingWriter{}
+	w := NewAsyncWriter(out, AsyncWriterOptions{FlushInterval: time.Hour})
+	defer w.Close()
+
+	_, err := w.Write([]byte("lost\n"))
+	require.NoError(t, err)
+	assert.EqualError(t, w.Flush(context.Background()), "disk full")
+
+	_, err = w.Write([]byte("kept\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush(context.Background()))
+	assert.Equal(t, "kept\n", out.buf.String())
+}
+
+func TestAsyncWriterFlushUnderLoad(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	close(out.gate)
+	w := NewAsyncWriter(out, AsyncWriterOptions{QueueSize: 4, Policy: OverflowBlock, BufferSize: 1, FlushInterval: time.Hour})
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_, _ = w.Write([]byte("line\n"))
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, w.Flush(ctx))
+
+	close(stop)
+	<-stopped
+	require.NoError(t, w.Close())
+}
+
+func TestAsyncWriterCloseWhileWriting(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	close(out.gate)
+	w := NewAsyncWriter(out, AsyncWriterOptions{QueueSize: 8, FlushInterval: time.Hour})
+
+	var accepted uint64
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := w.Write([]byte("line\n")); err != nil {
+					return
+				}
+				atomic.AddUint64(&accepted, 1)
+			}
+		}()
+	}
+
+	for atomic.LoadUint64(&accepted) < 100 {
+		runtime.Gosched()
+	}
+	require.NoError(t, w.Close())
+	wg.Wait()
+
+	stats := w.Stats()
+	assert.Equal(t, atomic.LoadUint64(&accepted), stats.Written+stats.Dropped)
+	assert.Equal(t, 0, stats.Queued)
+}
+
+func TestAsyncLoggerFatalFlushes(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(LoggerOptions{Async: true, Output: &out, Format: "text"})
+	require.NoError(t, err)
+	defer closeLogOutput(logger.Out)
+
+	exitCode := -1
+	logger.ExitFunc = flushingExit(logger, func(code int) { exitCode = code })
+	logger.Info("before")
+	logger.Fatal("fatal")
+
+	assert.Equal(t, 1, exitCode)
+	assert.Contains(t, out.String(), "msg=before")
+	assert.Contains(t, out.String(), "msg=fatal")
+}
+
+func TestLogFatalFlushesAsyncOutput(t *testing.T) {
+	var out bytes.Buffer
+	w := NewAsyncWriter(&out, AsyncWriterOptions{FlushInterval: time.Hour})
+	defer w.Close()
+
+	savedOut, savedExit := Log.Out, Log.ExitFunc
+	defer func() { Log.Out, Log.ExitFunc = savedOut, savedExit }()
+	Log.Out = w
+	Log.ExitFunc = func(int) {}
+
+	registerLogExitHandler()
+	Log.Fatal("fatal")
+	assert.Contains(t, out.String(), `"msg":"fatal"`)
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggerContextKey struct{}
+
+// contextLogger is the logger stored in context with fields added during the request
+type contextLogger struct {
+	logger logrus.FieldLogger
+
+	mu     sync.Mutex
+	fields logrus.Fields
+}
+
+// WithLogger returns context carrying logger, e.g. entry with request fields
+func WithLogger(ctx context.Context, logger logrus.FieldLogger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, &contextLogger{logger: logger, fields: make(logrus.Fields)})
+}
+
+// LoggerFromContext returns logger of ctx with fields added by AddLogFields, Log if ctx has no logger
+func LoggerFromContext(ctx context.Context) logrus.FieldLogger {
+	c, ok := ctx.Value(loggerContextKey{}).(*contextLogger)
+	if !ok {
+		return Log
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.fields) == 0 {
+		return c.logger
+	}
+	return c.logger.WithFields(c.fields)
+}
+
+// AddLogFields adds fields to the logger of ctx, they are included by every following LoggerFromContext
+// call including the "completed handling request" line of Middleware. It does nothing if ctx has no logger
+func AddLogFields(ctx context.Context, fields logrus.Fields) {
+	c, ok := ctx.Value(loggerContextKey{}).(*contextLogger)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range fields {
+		c.fields[key] = value
+	}
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Equal(t, Log, LoggerFromContext(context.Background()))
+	AddLogFields(context.Background(), logrus.Fields{"ignored": true})
+
+	entry := Log.WithField("xRequestId", "1")
+	ctx := WithLogger(context.Background(), entry)
+	assert.Equal(t, entry, LoggerFromContext(ctx))
+
+	AddLogFields(ctx, logrus.Fields{"user": "john"})
+	AddLogFields(context.WithValue(ctx, "other", 1), logrus.Fields{"order": 7})
+
+	logged := LoggerFromContext(ctx).(*logrus.Entry)
+	assert.Equal(t, logrus.Fields{"xRequestId": "1", "user": "john", "order": 7}, logged.Data)
+	assert.Equal(t, logrus.Fields{"xRequestId": "1"}, entry.Data)
+}
@@ -0,0 +1,199 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/itimofeev/go-util/cast"
+	"github.com/sirupsen/logrus"
+)
+
+// RootLogger is the name Log is controlled by in SetLogLevel and LogLevelHandler
+const RootLogger = "root"
+
+type controlledLogger struct {
+	logger *logrus.Logger
+	revert *time.Timer
+	base   logrus.Level
+}
+
+// components are loggers created by ComponentLogger, guarded by logMu
+var components = make(map[string]*controlledLogger)
+
+// ComponentLogger returns logger of the named component which writes like Log and adds the component field,
+// but has its own level controlled by SetLogLevel. Settings applied by SetLog are propagated to it
+func ComponentLogger(name string) *logrus.Logger {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	if c, ok := components[name]; ok {
+		return c.logger
+	}
+
+	logger := &logrus.Logger{
+		Level:        Log.GetLevel(),
+		Out:          Log.Out,
+		Formatter:    Log.Formatter,
+		Hooks:        componentHooks(Log.Hooks, name),
+		ReportCaller: Log.ReportCaller,
+		ExitFunc:     os.Exit,
+	}
+	components[name] = &controlledLogger{logger: logger}
+	return logger
+}
+
+func componentHooks(hooks logrus.LevelHooks, name string) logrus.LevelHooks {
+	copied := make(logrus.LevelHooks, len(hooks))
+	for level, levelHooks := range hooks {
+		copied[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	copied.Add(&staticFieldsHook{fields: logrus.Fields{"component": name}})
+	return copied
+}
+
+var rootLogger = &controlledLogger{logger: Log}
+
+func controlled(name string) (*controlledLogger, bool) {
+	if name == RootLogger || name == "" {
+		return rootLogger, true
+	}
+	c, ok := components[name]
+	return c, ok
+}
+
+// SetLogLevel sets level of the component logger or of Log for RootLogger.
+// If revertAfter is positive the level is restored after the timeout,
+// overriding the level again before that keeps the originally restored level
+func SetLogLevel(component string, level logrus.Level, revertAfter time.Duration) error {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	c, ok := controlled(component)
+	if !ok {
+		return fmt.Errorf("unknown logger %q", component)
+	}
+	setControlledLevel(c, level, revertAfter)
+	return nil
+}
+
+func setControlledLevel(c *controlledLogger, level logrus.Level, revertAfter time.Duration) {
+	if c.revert != nil {
+		c.revert.Stop()
+	} else {
+		c.base = c.logger.GetLevel()
+	}
+	c.revert = nil
+	c.logger.SetLevel(level)
+
+	if revertAfter > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(revertAfter, func() {
+			logMu.Lock()
+			defer logMu.Unlock()
+
+			if c.revert != timer {
+				return
+			}
+			c.revert = nil
+			c.logger.SetLevel(c.base)
+		})
+		c.revert = timer
+	}
+}
+
+// LogLevels returns levels of Log and component loggers by their names
+func LogLevels() map[string]string {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	levels := map[string]string{RootLogger: Log.GetLevel().String()}
+	for name, c := range components {
+		levels[name] = c.logger.GetLevel().String()
+	}
+	return levels
+}
+
+// maxLogLevelRequestSize limits the body of PUT requests to LogLevelHandler
+const maxLogLevelRequestSize = 1 << 10
+
+type logLevelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	Revert    string `json:"revert"`
+}
+
+// LogLevelHandler returns handler for the /debug/loglevel endpoint.
+// GET responds with LogLevels, PUT sets level with body like
+//
+//	{"component": "db", "level": "debug", "revert": "10m"}
+//
+// where component defaults to RootLogger and revert is optional.
+// Only Log and loggers returned by ComponentLogger are controlled, loggers created by NewLogger are not
+func LogLevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req logLevelRequest
+			r.Body = http.MaxBytesReader(w, r.Body, maxLogLevelRequestSize)
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			level, err := logrus.ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var revert time.Duration
+			if req.Revert != "" {
+				if revert, err = cast.TryDuration(req.Revert); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+
+			if err := SetLogLevel(req.Component, level, revert); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			Log.WithFields(logrus.Fields{"component": req.Component, "level": level.String(), "revert": revert}).
+				Warn("log level changed")
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set(contentType, applicationJSONCharsetUTF8)
+		_ = json.NewEncoder(w).Encode(LogLevels())
+	})
+}
+
+// shiftLogLevels moves levels of all loggers by delta steps, positive delta is more verbose
+func shiftLogLevels(delta int, revertAfter time.Duration) {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	root, _ := controlled(RootLogger)
+	loggers := []*controlledLogger{root}
+	for _, c := range components {
+		loggers = append(loggers, c)
+	}
+
+	for _, c := range loggers {
+		level := int(c.logger.GetLevel()) + delta
+		if level < int(logrus.ErrorLevel) {
+			level = int(logrus.ErrorLevel)
+		}
+		if level > int(logrus.TraceLevel) {
+			level = int(logrus.TraceLevel)
+		}
+		setControlledLevel(c, logrus.Level(level), revertAfter)
+	}
+}
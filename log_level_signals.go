@@ -0,0 +1,39 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// WatchLogLevelSignals makes all loggers of this package one level more verbose on SIGUSR1
+// and one level less verbose on SIGUSR2 until ctx is done.
+// If revertAfter is positive levels are restored after the timeout
+func WatchLogLevelSignals(ctx context.Context, revertAfter time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(signals)
+
+	watchLogLevelSignals(ctx, signals, revertAfter)
+}
+
+func watchLogLevelSignals(ctx context.Context, signals <-chan os.Signal, revertAfter time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			delta := 1
+			if sig == syscall.SIGUSR2 {
+				delta = -1
+			}
+			shiftLogLevels(delta, revertAfter)
+			Log.WithField("levels", LogLevels()).Warn("log levels changed by signal")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchLogLevelSignals(t *testing.T) {
+	logger := ComponentLogger("test-signals")
+	for name, level := range LogLevels() {
+		parsed, err := logrus.ParseLevel(level)
+		require.NoError(t, err)
+		defer SetLogLevel(name, parsed, 0)
+	}
+	require.NoError(t, SetLogLevel("test-signals", logrus.InfoLevel, 0))
+
+	// signals are subscribed before they are sent, otherwise SIGUSR1 would kill the test binary
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(signals)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchLogLevelSignals(ctx, signals, time.Minute)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.DebugLevel
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.InfoLevel
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,11 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// WatchLogLevelSignals waits until ctx is done, there are no SIGUSR1 and SIGUSR2 on windows
+func WatchLogLevelSignals(ctx context.Context, revertAfter time.Duration) {
+	<-ctx.Done()
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentLogger(t *testing.T) {
+	logger := ComponentLogger("test-db")
+	assert.Same(t, logger, ComponentLogger("test-db"))
+
+	var out bytes.Buffer
+	logger.SetOutput(&out)
+	defer logger.SetOutput(Log.Out)
+
+	require.NoError(t, SetLogLevel("test-db", logrus.WarnLevel, 0))
+	logger.Info("skipped")
+	logger.Warn("written")
+	assert.NotContains(t, out.String(), "skipped")
+	assert.Contains(t, out.String(), `"component":"test-db"`)
+	assert.Equal(t, "warning", LogLevels()["test-db"])
+	assert.Equal(t, logrus.DebugLevel, Log.GetLevel())
+
+	assert.Error(t, SetLogLevel("missing", logrus.InfoLevel, 0))
+}
+
+func TestSetLogLevelRevert(t *testing.T) {
+	logger := ComponentLogger("test-revert")
+	require.NoError(t, SetLogLevel("test-revert", logrus.InfoLevel, 0))
+
+	require.NoError(t, SetLogLevel("test-revert", logrus.TraceLevel, 20*time.Millisecond))
+	require.NoError(t, SetLogLevel("test-revert", logrus.DebugLevel, 20*time.Millisecond))
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.InfoLevel
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	ComponentLogger("test-http")
+	handler := LogLevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug/loglevel",
+		strings.NewReader(`{"component": "test-http", "level": "error", "revert": "1m"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var levels map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &levels))
+	assert.Equal(t, "error", levels["test-http"])
+	assert.Equal(t, "debug", levels[RootLogger])
+
+	for body, code := range map[string]int{
+		`{"level": "loud"}`:                                                    http.StatusBadRequest,
+		`{"level": "info", "revert": "soon"}`:                                  http.StatusBadRequest,
+		`{"component": "missing", "level": "info"}`:                            http.StatusNotFound,
+		`{"level": "info", "component": "` + strings.Repeat("x", 2<<10) + `"}`: http.StatusBadRequest,
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug/loglevel", strings.NewReader(body)))
+		assert.Equal(t, code, rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/loglevel", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
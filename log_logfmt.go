@@ -0,0 +1,108 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogfmtFormatter formats entries as logfmt lines: time, level and msg go first, then func and file
+// if the caller is reported, then fields sorted by key. Values with spaces, quotes, '=' or
+// non-printable characters are quoted, fields clashing with the leading keys are prefixed with "fields."
+type LogfmtFormatter struct {
+	// TimestampFormat defaults to LogTimestampFormat
+	TimestampFormat string
+}
+
+// Format renders a single log entry
+func (f *LogfmtFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := entry.Buffer
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = LogTimestampFormat
+	}
+
+	writeLogfmtPair(b, logrus.FieldKeyTime, entry.Time.Format(timestampFormat))
+	writeLogfmtPair(b, logrus.FieldKeyLevel, entry.Level.String())
+	writeLogfmtPair(b, logrus.FieldKeyMsg, entry.Message)
+	if entry.HasCaller() {
+		writeLogfmtPair(b, logrus.FieldKeyFunc, entry.Caller.Function)
+		writeLogfmtPair(b, logrus.FieldKeyFile, fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line))
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := key
+		switch key {
+		case logrus.FieldKeyTime, logrus.FieldKeyLevel, logrus.FieldKeyMsg, logrus.FieldKeyFunc, logrus.FieldKeyFile:
+			name = "fields." + key
+		}
+		writeLogfmtPair(b, name, logfmtValue(entry.Data[key]))
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	if logfmtNeedsQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func logfmtValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// logfmtKey replaces characters not allowed in keys with '_'
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtNeedsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	var out bytes.Buffer
+	logger := &logrus.Logger{
+		Out:       &out,
+		Level:     logrus.InfoLevel,
+		Formatter: &LogfmtFormatter{TimestampFormat: time.RFC3339},
+		Hooks:     make(logrus.LevelHooks),
+	}
+
+	logger.WithFields(logrus.Fields{
+		"user":     "john doe",
+		"count":    3,
+		"empty":    "",
+		"query":    `a="b"`,
+		"err":      errors.New("failed"),
+		"msg":      "clash",
+		"bad key":  "x",
+		"multi\nx": "line\nbreak",
+	}).Info("request done")
+
+	line := out.String()
+	require.Contains(t, line, " level=info msg=\"request done\" ")
+	assert.Regexp(t, `^time=\S+ level=info`, line)
+	assert.Contains(t, line, `bad_key=x count=3 empty="" err=failed fields.msg=clash multi_x="line\nbreak" query="a=\"b\"" user="john doe"`+"\n")
+}
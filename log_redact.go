@@ -0,0 +1,313 @@
+package util
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactPattern describes sensitive values found in strings
+type RedactPattern struct {
+	Regexp *regexp.Regexp
+	// Replace returns replacement of the match, nil replaces the whole match with the mask
+	Replace func(match string) string
+}
+
+var (
+	// BearerTokenPattern masks tokens of Authorization: Bearer headers
+	BearerTokenPattern = RedactPattern{
+		Regexp: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		Replace: func(match string) string {
+			return strings.Fields(match)[0] + " " + maskedValue
+		},
+	}
+	// EmailPattern masks email addresses
+	EmailPattern = RedactPattern{
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+	// CardNumberPattern masks numbers of 13 to 19 digits, optionally separated by spaces or dashes,
+	// which pass the Luhn check
+	CardNumberPattern = RedactPattern{
+		Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Replace: func(match string) string {
+			if !luhnValid(match) {
+				return match
+			}
+			return maskedValue
+		},
+	}
+)
+
+// DefaultRedactFields are parts of field names masked by DefaultRedactor
+var DefaultRedactFields = []string{"password", "passwd", "secret", "token", "apikey", "authorization", "cookie"}
+
+// maxRedactDepth limits nesting of redacted values, deeper values are masked
+const maxRedactDepth = 16
+
+// Redactor masks values of sensitive fields and sensitive parts of strings in log entries
+type Redactor struct {
+	fields   []string
+	patterns []RedactPattern
+}
+
+// NewRedactor returns redactor masking values of fields with names containing any of fields,
+// case, underscores and dashes are ignored, so "api_key" matches "APIKey" and "X-Api-Key".
+// Strings everywhere else are checked with patterns
+func NewRedactor(fields []string, patterns ...RedactPattern) *Redactor {
+	normalized := make([]string, 0, len(fields))
+	for _, field := range fields {
+		normalized = append(normalized, normalizeFieldName(field))
+	}
+	return &Redactor{fields: normalized, patterns: patterns}
+}
+
+// DefaultRedactor returns redactor of DefaultRedactFields with bearer token, email and card number patterns
+func DefaultRedactor() *Redactor {
+	return NewRedactor(DefaultRedactFields, BearerTokenPattern, EmailPattern, CardNumberPattern)
+}
+
+// Formatter returns formatter redacting message and fields of entries before formatting them with f,
+// nested maps, slices and structs are redacted as they are serialized to json. Values implementing
+// json.Marshaler and byte slices are redacted after decoding if they hold json, otherwise as text
+// where values of key=value pairs with sensitive keys are masked too
+func (r *Redactor) Formatter(f logrus.Formatter) logrus.Formatter {
+	return &redactingFormatter{Formatter: f, redactor: r}
+}
+
+type redactingFormatter struct {
+	logrus.Formatter
+	redactor *Redactor
+}
+
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	redacted := *entry
+	redacted.Message = f.redactor.String(entry.Message)
+	redacted.Data = make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		redacted.Data[key] = f.redactor.field(key, value, 0)
+	}
+	return f.Formatter.Format(&redacted)
+}
+
+// String masks sensitive parts of s
+func (r *Redactor) String(s string) string {
+	for _, p := range r.patterns {
+		replace := p.Replace
+		if replace == nil {
+			replace = func(string) string { return maskedValue }
+		}
+		s = p.Regexp.ReplaceAllStringFunc(s, replace)
+	}
+	return s
+}
+
+// Value returns copy of v with sensitive fields and strings masked, maps and structs are converted
+// to map[string]interface{} and slices to []interface{}
+func (r *Redactor) Value(v interface{}) interface{} {
+	return r.value(v, 0)
+}
+
+func (r *Redactor) isSensitive(name string) bool {
+	name = normalizeFieldName(name)
+	for _, field := range r.fields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) field(name string, v interface{}, depth int) interface{} {
+	if r.isSensitive(name) && v != nil {
+		return maskedValue
+	}
+	return r.value(v, depth)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (r *Redactor) value(v interface{}, depth int) interface{} {
+	if depth > maxRedactDepth {
+		return maskedValue
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.String(val)
+	case error:
+		return r.String(val.Error())
+	case time.Time, time.Duration, json.Number:
+		return val
+	}
+
+	rv := reflect.ValueOf(v)
+	t := rv.Type()
+	switch {
+	case t.Implements(jsonMarshalerType):
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		data, err := v.(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return r.String(err.Error())
+		}
+		return r.bytes(data, depth)
+	case t.Implements(textMarshalerType):
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		text, err := v.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return r.String(err.Error())
+		}
+		return r.text(string(text))
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return r.value(rv.Elem().Interface(), depth+1)
+	case reflect.String:
+		return r.String(rv.String())
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		redacted := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := displayValue(iter.Key())
+			redacted[key] = r.field(key, iter.Value().Interface(), depth+1)
+		}
+		return redacted
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && rv.Kind() == reflect.Slice {
+			if rv.IsNil() {
+				return nil
+			}
+			return r.bytes(rv.Bytes(), depth)
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		redacted := make([]interface{}, rv.Len())
+		for i := range redacted {
+			redacted[i] = r.value(rv.Index(i).Interface(), depth+1)
+		}
+		return redacted
+	case reflect.Struct:
+		redacted := make(map[string]interface{}, t.NumField())
+		r.structFields(rv, redacted, depth)
+		return redacted
+	default:
+		return v
+	}
+}
+
+// bytes redacts data decoded if it is a json value or its text otherwise, e.g. raw request bodies
+func (r *Redactor) bytes(data []byte, depth int) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err == nil {
+		if _, err := dec.Token(); err == io.EOF {
+			return r.value(decoded, depth+1)
+		}
+	}
+	return r.text(string(data))
+}
+
+// redactPairRegexp matches key=value and "key": "value" pairs in texts like form or json bodies
+var redactPairRegexp = regexp.MustCompile(`"?([A-Za-z0-9_.\-]+)"?\s*[=:]\s*("[^"]*"|[^\s&,;"]+)`)
+
+// text masks sensitive parts of s like String and also values of pairs with sensitive keys
+func (r *Redactor) text(s string) string {
+	return redactPairRegexp.ReplaceAllStringFunc(r.String(s), func(match string) string {
+		m := redactPairRegexp.FindStringSubmatchIndex(match)
+		if !r.isSensitive(match[m[2]:m[3]]) {
+			return match
+		}
+		if strings.HasPrefix(match[m[4]:m[5]], `"`) {
+			return match[:m[4]] + `"` + maskedValue + `"`
+		}
+		return match[:m[4]] + maskedValue
+	})
+}
+
+// structFields adds exported fields of struct v named as encoding/json names them
+func (r *Redactor) structFields(v reflect.Value, redacted map[string]interface{}, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		name := sf.Name
+		omitEmpty := false
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && name == sf.Name && fv.Kind() == reflect.Struct {
+			r.structFields(fv, redacted, depth)
+			continue
+		}
+		if sf.PkgPath != "" || omitEmpty && fv.IsZero() {
+			continue
+		}
+		if r.isSensitive(sf.Name) && !fv.IsZero() {
+			redacted[name] = maskedValue
+			continue
+		}
+		redacted[name] = r.field(name, fv.Interface(), depth+1)
+	}
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+// luhnValid reports whether digits of s pass the Luhn check
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
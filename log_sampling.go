@@ -0,0 +1,137 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// suppressedField is set in summaries logged by Sampler.Run, such entries are never sampled
+const suppressedField = "suppressed"
+
+// Sampler limits repetitive log entries: for every key it passes the first entries per interval
+// and then every thereafter-th one. Entries at error level and above always pass
+type Sampler struct {
+	first      int
+	thereafter int
+	interval   time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	counters map[string]*samplingCounter
+}
+
+type samplingCounter struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// defaultSamplingInterval is used by NewSampler for non-positive intervals
+const defaultSamplingInterval = time.Second
+
+// NewSampler returns sampler passing first entries with the same key per interval and then every thereafter-th,
+// thereafter 0 suppresses all the rest. Non-positive interval is replaced with one second
+func NewSampler(first, thereafter int, interval time.Duration) *Sampler {
+	if interval <= 0 {
+		interval = defaultSamplingInterval
+	}
+	return &Sampler{
+		first:      first,
+		thereafter: thereafter,
+		interval:   interval,
+		now:        time.Now,
+		counters:   make(map[string]*samplingCounter),
+	}
+}
+
+// Allow reports whether entry with level and key should be written
+func (s *Sampler) Allow(level logrus.Level, key string) bool {
+	if level <= logrus.ErrorLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &samplingCounter{start: now}
+		s.counters[key] = c
+	} else if now.Sub(c.start) >= s.interval {
+		c.start, c.count = now, 0
+	}
+
+	c.count++
+	if c.count <= s.first || s.thereafter > 0 && (c.count-s.first)%s.thereafter == 0 {
+		return true
+	}
+	c.suppressed++
+	return false
+}
+
+// Suppressed returns numbers of entries suppressed since the previous call by their keys
+func (s *Sampler) Suppressed() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	suppressed := make(map[string]int)
+	for key, c := range s.counters {
+		if c.suppressed > 0 {
+			suppressed[key] = c.suppressed
+			c.suppressed = 0
+		} else if now.Sub(c.start) >= s.interval {
+			delete(s.counters, key)
+		}
+	}
+	return suppressed
+}
+
+// Run logs summary of suppressed entries to log every interval until ctx is done
+func (s *Sampler) Run(ctx context.Context, log logrus.FieldLogger) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logSuppressed(log)
+			return
+		case <-ticker.C:
+			s.logSuppressed(log)
+		}
+	}
+}
+
+func (s *Sampler) logSuppressed(log logrus.FieldLogger) {
+	for key, n := range s.Suppressed() {
+		log.WithFields(logrus.Fields{"sampledKey": key, suppressedField: n}).Warn("suppressed log messages")
+	}
+}
+
+// Formatter returns formatter sampling entries by level and message before formatting them with f, e.g.
+//
+//	logger.SetFormatter(sampler.Formatter(logger.Formatter))
+//	go sampler.Run(ctx, logger)
+//
+// Suppressed entries are formatted as empty output, AsyncWriter and RotatingFile skip such writes.
+// Hooks of the logger still fire for every entry as logrus calls them before formatting
+func (s *Sampler) Formatter(f logrus.Formatter) logrus.Formatter {
+	return &samplingFormatter{Formatter: f, sampler: s}
+}
+
+type samplingFormatter struct {
+	logrus.Formatter
+	sampler *Sampler
+}
+
+func (f *samplingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if _, summary := entry.Data[suppressedField]; !summary && !f.sampler.Allow(entry.Level, entry.Level.String()+": "+entry.Message) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
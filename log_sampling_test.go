@@ -0,0 +1,94 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	sampler := NewSampler(2, 3, time.Second)
+	sampler.now = func() time.Time { return now }
+
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if sampler.Allow(logrus.InfoLevel, "key") {
+			allowed = append(allowed, i)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 5, 8}, allowed)
+	assert.True(t, sampler.Allow(logrus.ErrorLevel, "key"))
+	assert.True(t, sampler.Allow(logrus.InfoLevel, "other"))
+
+	now = now.Add(time.Second)
+	assert.True(t, sampler.Allow(logrus.InfoLevel, "key"))
+	assert.Equal(t, map[string]int{"key": 6}, sampler.Suppressed())
+	assert.Empty(t, sampler.Suppressed())
+
+	now = now.Add(time.Second)
+	sampler.Suppressed()
+	assert.Empty(t, sampler.counters)
+}
+
+func TestSamplerDefaultInterval(t *testing.T) {
+	sampler := NewSampler(1, 0, 0)
+	assert.Equal(t, defaultSamplingInterval, sampler.interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sampler.Run(ctx, logrus.New())
+}
+
+func TestSamplerFormatter(t *testing.T) {
+	var out bytes.Buffer
+	sampler := NewSampler(1, 0, time.Hour)
+	logger := &logrus.Logger{Out: &out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks)}
+	logger.SetFormatter(sampler.Formatter(&logrus.TextFormatter{DisableColors: true, DisableTimestamp: true}))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("repeated")
+		logger.Error("failed")
+	}
+	sampler.logSuppressed(logger)
+
+	assert.Equal(t, 1, strings.Count(out.String(), "msg=repeated"))
+	assert.Equal(t, 3, strings.Count(out.String(), "msg=failed"))
+	assert.Contains(t, out.String(), `msg="suppressed log messages" sampledKey="info: repeated" suppressed=2`)
+}
+
+func TestSamplerFormatterAsyncWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewAsyncWriter(&out, AsyncWriterOptions{FlushInterval: time.Hour})
+	defer w.Close()
+
+	sampler := NewSampler(1, 0, time.Hour)
+	logger := &logrus.Logger{Out: w, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks)}
+	logger.SetFormatter(sampler.Formatter(&logrus.TextFormatter{DisableColors: true, DisableTimestamp: true}))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("repeated")
+	}
+	require.NoError(t, w.Flush(context.Background()))
+
+	assert.Equal(t, "level=info msg=repeated\n", out.String())
+	assert.Equal(t, AsyncWriterStats{Written: 1}, w.Stats())
+}
+
+func TestSamplerRun(t *testing.T) {
+	var out bytes.Buffer
+	sampler := NewSampler(0, 0, time.Hour)
+	logger := &logrus.Logger{Out: &out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+	sampler.Allow(logrus.InfoLevel, "key")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sampler.Run(ctx, logger)
+	assert.Contains(t, out.String(), `"suppressed":1`)
+}
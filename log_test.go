@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/itimofeev/go-util/logtest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogWithError(t *testing.T) {
+	hook, restore := logtest.Capture(Log)
+	defer restore()
+
+	LogWithError(Log, errors.New("failed"), "saving")
+	LogWithError(Log, nil, "saved")
+
+	assert.True(t, hook.HasEntry(logrus.ErrorLevel, "saving", logrus.Fields{"error": "failed"}))
+	assert.True(t, hook.HasEntry(logrus.DebugLevel, "saved", nil))
+}
+
+// flushRecorder is the log output remembering what was written before Flush
+type flushRecorder struct {
+	written []byte
+	flushed []byte
+}
+
+func (w *flushRecorder) Write(p []byte) (int, error) {
+	w.written = append(w.written, p...)
+	return len(p), nil
+}
+
+func (w *flushRecorder) Flush(context.Context) error {
+	w.flushed = append([]byte(nil), w.written...)
+	return nil
+}
+
+func TestLogStartStopFlushesBeforePanic(t *testing.T) {
+	out := &flushRecorder{}
+	logger := &logrus.Logger{Out: out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer LogStartStop(logger, "1.0", "test")()
+		panic("boom")
+	})
+	assert.Contains(t, string(out.flushed), `"msg":"exited with error"`)
+}
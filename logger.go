@@ -0,0 +1,345 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LoggerOptions configures NewLogger, it can be loaded from env with LoadConfig
+type LoggerOptions struct {
+	// Level is one of trace, debug, info, warn, error, fatal, panic
+	Level string `env:"LOG_LEVEL" default:"debug" validate:"oneof=trace debug info warn warning error fatal panic"`
+	// Format is one of json, text or logfmt
+	Format string `env:"LOG_FORMAT" default:"json" validate:"oneof=json text logfmt"`
+	// TimestampFormat defaults to LogTimestampFormat
+	TimestampFormat string `env:"LOG_TIMESTAMP_FORMAT"`
+	// UTC converts timestamps to UTC, otherwise they are logged in the local time zone
+	UTC bool `env:"LOG_UTC"`
+	// Caller adds the calling function and file to entries
+	Caller bool `env:"LOG_CALLER"`
+
+	// File is the path of the log file, logs are written to Output if it is empty
+	File string `env:"LOG_FILE"`
+	// MaxSizeMB is the size of the log file it is rotated at, 0 disables rotation
+	MaxSizeMB int `env:"LOG_MAX_SIZE_MB" default:"100" validate:"min=0"`
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int `env:"LOG_MAX_BACKUPS" default:"5" validate:"min=0"`
+	// Output is used if File is empty, os.Stdout by default
+	Output io.Writer
+
+	// Async writes entries from the background goroutine through AsyncWriter, see FlushLog
+	Async bool `env:"LOG_ASYNC"`
+	// AsyncQueueSize is the number of entries queued by AsyncWriter
+	AsyncQueueSize int `env:"LOG_ASYNC_QUEUE_SIZE" default:"1024" validate:"min=0"`
+	// AsyncBlock makes logging wait for the free space in the queue instead of dropping entries
+	AsyncBlock bool `env:"LOG_ASYNC_BLOCK"`
+
+	// Fields are added to every entry, e.g. service=arp,version=1.2
+	Fields map[string]string `env:"LOG_FIELDS"`
+	// Host adds the host field with the host name
+	Host bool `env:"LOG_HOST"`
+
+	// Redact masks sensitive fields and values with DefaultRedactor
+	Redact bool `env:"LOG_REDACT"`
+	// RedactFields are masked in addition to DefaultRedactFields if Redact is set
+	RedactFields []string `env:"LOG_REDACT_FIELDS"`
+}
+
+// NewLogger returns logger configured by opts, e.g.
+//
+//	var opts util.LoggerOptions
+//	err := util.LoadConfig(&opts)
+//	...
+//	logger, err := util.NewLogger(opts)
+//
+// The returned logger isn't controlled by SetLogLevel, LogLevelHandler and WatchLogLevelSignals
+// unless it is applied to Log with SetLog
+func NewLogger(opts LoggerOptions) (*logrus.Logger, error) {
+	level := logrus.DebugLevel
+	if opts.Level != "" {
+		var err error
+		if level, err = logrus.ParseLevel(opts.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	timestampFormat := opts.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = LogTimestampFormat
+	}
+
+	var formatter logrus.Formatter
+	switch strings.ToLower(opts.Format) {
+	case "", "json":
+		formatter = &logrus.JSONFormatter{TimestampFormat: timestampFormat}
+	case "text":
+		formatter = &logrus.TextFormatter{TimestampFormat: timestampFormat, FullTimestamp: true}
+	case "logfmt":
+		formatter = &LogfmtFormatter{TimestampFormat: timestampFormat}
+	default:
+		return nil, fmt.Errorf("unknown log format %q", opts.Format)
+	}
+	if opts.Redact {
+		fields := append(append([]string(nil), DefaultRedactFields...), opts.RedactFields...)
+		formatter = NewRedactor(fields, BearerTokenPattern, EmailPattern, CardNumberPattern).Formatter(formatter)
+	}
+	if opts.UTC {
+		formatter = &utcFormatter{Formatter: formatter}
+	}
+
+	fields := make(logrus.Fields, len(opts.Fields)+1)
+	for key, value := range opts.Fields {
+		fields[key] = value
+	}
+	if opts.Host {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		fields["host"] = host
+	}
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	if opts.File != "" {
+		file, err := OpenRotatingFile(opts.File, int64(opts.MaxSizeMB)<<20, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out = file
+	}
+	if opts.Async {
+		policy := OverflowDrop
+		if opts.AsyncBlock {
+			policy = OverflowBlock
+		}
+		out = NewAsyncWriter(out, AsyncWriterOptions{QueueSize: opts.AsyncQueueSize, Policy: policy})
+		registerLogExitHandler()
+	}
+
+	logger := &logrus.Logger{
+		Level:        level,
+		Out:          out,
+		Formatter:    formatter,
+		Hooks:        make(logrus.LevelHooks),
+		ReportCaller: opts.Caller,
+	}
+	logger.ExitFunc = flushingExit(logger, os.Exit)
+	if len(fields) > 0 {
+		logger.AddHook(&staticFieldsHook{fields: fields})
+	}
+	return logger, nil
+}
+
+var logMu sync.Mutex
+
+// SetLog applies level, output, formatter, hooks and caller reporting of logger to Log,
+// so the loggers and entries already derived from Log pick up the new settings.
+// Component loggers get the same settings except the level.
+// Pending revert of the Log level set by SetLogLevel is cancelled.
+// The previous output of Log is flushed and closed if it was opened by NewLogger
+func SetLog(logger *logrus.Logger) {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	previous := Log.Out
+
+	Log.SetFormatter(logger.Formatter)
+	Log.SetOutput(logger.Out)
+	Log.ReplaceHooks(logger.Hooks)
+	Log.SetReportCaller(logger.ReportCaller)
+	Log.SetLevel(logger.GetLevel())
+	if rootLogger.revert != nil {
+		rootLogger.revert.Stop()
+		rootLogger.revert = nil
+	}
+
+	for name, c := range components {
+		c.logger.SetFormatter(logger.Formatter)
+		c.logger.SetOutput(logger.Out)
+		c.logger.ReplaceHooks(componentHooks(logger.Hooks, name))
+		c.logger.SetReportCaller(logger.ReportCaller)
+	}
+
+	if previous != logger.Out {
+		closeLogOutput(previous)
+	}
+}
+
+// closeLogOutput closes outputs opened by NewLogger
+func closeLogOutput(out io.Writer) {
+	switch w := out.(type) {
+	case *AsyncWriter:
+		_ = w.Close()
+		closeLogOutput(w.out)
+	case *RotatingFile:
+		_ = w.Close()
+	}
+}
+
+// ConfigureLog configures Log with NewLogger(opts)
+func ConfigureLog(opts LoggerOptions) error {
+	logger, err := NewLogger(opts)
+	if err != nil {
+		return err
+	}
+	SetLog(logger)
+	return nil
+}
+
+type utcFormatter struct {
+	logrus.Formatter
+}
+
+func (f *utcFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	entry.Time = entry.Time.UTC()
+	return f.Formatter.Format(entry)
+}
+
+// staticFieldsHook adds fields to entries which don't have them
+type staticFieldsHook struct {
+	fields logrus.Fields
+}
+
+func (h *staticFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *staticFieldsHook) Fire(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(entry.Data)+len(h.fields))
+	for key, value := range h.fields {
+		data[key] = value
+	}
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+	entry.Data = data
+	return nil
+}
+
+// RotatingFile is a log file which is renamed to path.1 when it grows over the max size,
+// the earlier backups are shifted to path.2 and so on up to the max number of backups
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens or creates file at path for appending, maxSize 0 disables rotation
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if rotateErr = f.rotate(); rotateErr != nil && f.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// rotate moves the file to the backups and opens the new one. If moving fails the original file
+// is reopened and the next rotation is tried after another maxSize bytes, so the error is reported once
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err == nil {
+		err = f.shiftBackups()
+	}
+	if err != nil {
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		f.size = 0
+		return err
+	}
+	return f.open()
+}
+
+func (f *RotatingFile) shiftBackups() error {
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxBackups))
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", f.path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", f.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(f.path, f.path+".1")
+}
+
+// Sync commits the written entries to the storage
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close closes the file, writes after Close fail
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
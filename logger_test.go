@@ -0,0 +1,150 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(LoggerOptions{
+		Level:  "info",
+		Format: "json",
+		UTC:    true,
+		Caller: true,
+		Output: &out,
+		Fields: map[string]string{"service": "arp", "version": "1.0"},
+	})
+	require.NoError(t, err)
+
+	logger.Debug("skipped")
+	logger.WithField("version", "override").Info("hello")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "hello", entry["msg"])
+	assert.Equal(t, "arp", entry["service"])
+	assert.Equal(t, "override", entry["version"])
+	assert.Contains(t, entry, "file")
+
+	ts, err := time.Parse(LogTimestampFormat, entry["time"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().UTC(), ts, time.Minute)
+
+	out.Reset()
+	logger, err = NewLogger(LoggerOptions{Format: "logfmt", Output: &out})
+	require.NoError(t, err)
+	logger.WithField("key", "value").Debug("plain")
+	assert.Contains(t, out.String(), `level=debug msg=plain key=value`)
+
+	_, err = NewLogger(LoggerOptions{Level: "loud"})
+	assert.Error(t, err)
+	_, err = NewLogger(LoggerOptions{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	file, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	for name, content := range map[string]string{"app.log": "fourth\n", "app.log.1": "third\n", "app.log.2": "second\n"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = file.Write([]byte("closed"))
+	assert.Error(t, err)
+}
+
+func TestRotatingFileRotationFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	blocker := filepath.Join(path+".1", "keep")
+	require.NoError(t, os.MkdirAll(blocker, 0755))
+
+	file, err := OpenRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("second\n"))
+	assert.Error(t, err)
+	_, err = file.Write([]byte("3\n"))
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n3\n", string(data))
+
+	require.NoError(t, os.RemoveAll(path+".1"))
+	_, err = file.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(data))
+}
+
+func TestSetLog(t *testing.T) {
+	defer SetLog(&logrus.Logger{
+		Level:     logrus.DebugLevel,
+		Out:       os.Stdout,
+		Formatter: &logrus.JSONFormatter{TimestampFormat: LogTimestampFormat},
+		Hooks:     make(logrus.LevelHooks),
+	})
+
+	entry := Log.WithField("component", "test")
+
+	var out bytes.Buffer
+	require.NoError(t, ConfigureLog(LoggerOptions{Level: "warn", Format: "logfmt", Output: &out}))
+	entry.Info("skipped")
+	entry.Warn("swapped")
+
+	assert.NotContains(t, out.String(), "skipped")
+	assert.Contains(t, out.String(), "msg=swapped component=test")
+}
+
+func TestSetLogCancelsLevelRevert(t *testing.T) {
+	defer SetLog(&logrus.Logger{
+		Level:     logrus.DebugLevel,
+		Out:       Log.Out,
+		Formatter: Log.Formatter,
+		Hooks:     make(logrus.LevelHooks),
+	})
+
+	require.NoError(t, SetLogLevel(RootLogger, logrus.TraceLevel, time.Hour))
+	SetLog(&logrus.Logger{Level: logrus.WarnLevel, Out: Log.Out, Formatter: Log.Formatter, Hooks: make(logrus.LevelHooks)})
+
+	logMu.Lock()
+	revert := rootLogger.revert
+	logMu.Unlock()
+	assert.Nil(t, revert)
+	assert.Equal(t, logrus.WarnLevel, Log.GetLevel())
+}
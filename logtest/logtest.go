@@ -0,0 +1,150 @@
+// Package logtest captures logrus entries in tests
+package logtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Hook keeps entries of all levels in memory
+type Hook struct {
+	mu      sync.Mutex
+	entries []logrus.Entry
+}
+
+// NewHook returns empty hook, add it with logger.AddHook
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+// Levels returns all levels
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire keeps copy of the entry
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	copied := *entry
+	copied.Data = make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		copied.Data[key] = value
+	}
+	copied.Buffer = nil
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, copied)
+	return nil
+}
+
+// Entries returns the captured entries
+func (h *Hook) Entries() []logrus.Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]logrus.Entry(nil), h.entries...)
+}
+
+// LastEntry returns the last captured entry or nil
+func (h *Hook) LastEntry() *logrus.Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) == 0 {
+		return nil
+	}
+	entry := h.entries[len(h.entries)-1]
+	return &entry
+}
+
+// HasEntry reports whether entry with level, msg and fields was captured. Entry may have other fields,
+// field values are equal if they are deeply equal or print the same, so errors match their messages
+func (h *Hook) HasEntry(level logrus.Level, msg string, fields logrus.Fields) bool {
+	for _, entry := range h.Entries() {
+		if entry.Level == level && entry.Message == msg && hasFields(entry.Data, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFields(data, fields logrus.Fields) bool {
+	for key, want := range fields {
+		got, ok := data[key]
+		if !ok {
+			return false
+		}
+		if !reflect.DeepEqual(got, want) && fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset removes the captured entries
+func (h *Hook) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = nil
+}
+
+// NewLogger returns logger of all levels which discards output and captures entries with the returned hook
+func NewLogger() (*logrus.Logger, *Hook) {
+	hook := NewHook()
+	logger := &logrus.Logger{
+		Out:       ioutil.Discard,
+		Formatter: &logrus.TextFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.TraceLevel,
+	}
+	logger.AddHook(hook)
+	return logger, hook
+}
+
+// Capture adds hook to logger, e.g. util.Log, until restore is called:
+//
+//	hook, restore := logtest.Capture(util.Log)
+//	defer restore()
+func Capture(logger *logrus.Logger) (hook *Hook, restore func()) {
+	hook = NewHook()
+
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logger.Hooks {
+		hooks[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	hooks.Add(hook)
+	previous := logger.ReplaceHooks(hooks)
+
+	return hook, func() {
+		logger.ReplaceHooks(previous)
+	}
+}
+
+// NewTestLogger returns logger of all levels writing through t.Log,
+// so the output is shown with the failing test
+func NewTestLogger(t testing.TB) *logrus.Logger {
+	return &logrus.Logger{
+		Out:       &testWriter{t: t},
+		Formatter: &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.TraceLevel,
+	}
+}
+
+type testWriter struct {
+	t testing.TB
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimRight(string(bytes.TrimRight(p, "\n")), "\r"))
+	return len(p), nil
+}
@@ -0,0 +1,61 @@
+package logtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	logger, hook := NewLogger()
+
+	entry := logger.WithField("user", "john")
+	entry.WithError(errors.New("denied")).Warn("login failed")
+	entry.Debug("retrying")
+
+	assert.True(t, hook.HasEntry(logrus.WarnLevel, "login failed", logrus.Fields{"user": "john", "error": "denied"}))
+	assert.True(t, hook.HasEntry(logrus.DebugLevel, "retrying", nil))
+	assert.False(t, hook.HasEntry(logrus.InfoLevel, "retrying", nil))
+	assert.False(t, hook.HasEntry(logrus.WarnLevel, "login failed", logrus.Fields{"user": "jane"}))
+	assert.False(t, hook.HasEntry(logrus.WarnLevel, "login failed", logrus.Fields{"missing": 1}))
+
+	require.Len(t, hook.Entries(), 2)
+	assert.Equal(t, "retrying", hook.LastEntry().Message)
+	assert.Equal(t, logrus.Fields{"user": "john"}, entry.Data)
+
+	hook.Reset()
+	assert.Empty(t, hook.Entries())
+	assert.Nil(t, hook.LastEntry())
+}
+
+func TestCapture(t *testing.T) {
+	logger, existing := NewLogger()
+
+	hook, restore := Capture(logger)
+	logger.Info("captured")
+	restore()
+	logger.Info("after restore")
+
+	assert.True(t, hook.HasEntry(logrus.InfoLevel, "captured", nil))
+	assert.False(t, hook.HasEntry(logrus.InfoLevel, "after restore", nil))
+	assert.Len(t, existing.Entries(), 2)
+}
+
+type recordingTB struct {
+	testing.TB
+	logged []string
+}
+
+func (r *recordingTB) Log(args ...interface{}) {
+	r.logged = append(r.logged, args[0].(string))
+}
+
+func TestNewTestLogger(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	NewTestLogger(tb).WithField("key", "value").Info("through t.Log")
+
+	assert.Equal(t, []string{`level=info msg="through t.Log" key=value`}, tb.logged)
+}
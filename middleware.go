@@ -38,22 +38,32 @@ type Middleware struct {
 
 	// Exclude URLs from logging
 	excludeURLs []string
+
+	// Samplers of URLs
+	samplers map[string]*Sampler
 }
 
-// NewDefaultMiddleware returns a new *Middleware which writes to a given logrus addedLogs.
-func NewDefaultMiddleware(logger logrus.FieldLogger, requestIDContextKey string) func(handler http.Handler) http.Handler {
-	mw := &Middleware{
+// NewMiddleware returns a new *Middleware which writes to a given logrus logger.
+func NewMiddleware(logger logrus.FieldLogger, requestIDContextKey string) *Middleware {
+	return &Middleware{
 		Logger:              logger,
 		requestIDContextKey: requestIDContextKey,
 		logStarting:         true,
 		clock:               &realClock{},
+		samplers:            make(map[string]*Sampler),
 	}
+}
 
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			mw.ServeHTTP(w, r, next.ServeHTTP)
-		})
-	}
+// NewDefaultMiddleware returns a new *Middleware which writes to a given logrus addedLogs.
+func NewDefaultMiddleware(logger logrus.FieldLogger, requestIDContextKey string) func(handler http.Handler) http.Handler {
+	return NewMiddleware(logger, requestIDContextKey).Handler
+}
+
+// Handler wraps next with the middleware
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.ServeHTTP(w, r, next.ServeHTTP)
+	})
 }
 
 // SetLogStarting accepts a bool to control the logging of "started handling
@@ -76,6 +86,27 @@ func (m *Middleware) ExcludedURLs() []string {
 	return m.excludeURLs
 }
 
+// SampleURL makes requests to URL u logged through sampler. The sampler decides once per request whether
+// both started and completed lines are logged, the completed line of requests failed with 5xx status or panic
+// is logged anyway. Run sampler.Run to get summaries of suppressed requests.
+// The URL u is parsed, hence the returned error
+func (m *Middleware) SampleURL(u string, sampler *Sampler) error {
+	if _, err := url.Parse(u); err != nil {
+		return err
+	}
+	if m.samplers == nil {
+		m.samplers = make(map[string]*Sampler)
+	}
+	m.samplers[u] = sampler
+	return nil
+}
+
+// sampled reports whether the request is logged according to the sampler of its URL
+func (m *Middleware) sampled(r *http.Request) bool {
+	sampler, ok := m.samplers[r.URL.Path]
+	return !ok || sampler.Allow(logrus.DebugLevel, r.URL.Path)
+}
+
 var contentType = http.CanonicalHeaderKey("Content-Type")
 
 const (
@@ -106,7 +137,6 @@ func (m *Middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next htt
 	}
 
 	ctx := context.WithValue(r.Context(), m.requestIDContextKey, reqID)
-	r = r.WithContext(ctx)
 	entry = entry.WithField("xRequestId", reqID)
 
 	// Try to get the real IP
@@ -121,7 +151,11 @@ func (m *Middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next htt
 		"remote":  remoteAddr,
 	})
 
-	if m.logStarting {
+	ctx = WithLogger(ctx, entry)
+	r = r.WithContext(ctx)
+
+	logged := m.sampled(r)
+	if m.logStarting && logged {
 		entry.Debug("started handling request")
 	}
 
@@ -129,7 +163,10 @@ func (m *Middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next htt
 	decorator.writer.Header().Add("X-Request-Id", reqID)
 
 	defer func() {
-		if err := recover(); err != nil {
+		entry := LoggerFromContext(ctx)
+
+		err := recover()
+		if err != nil {
 			frames := stack.Callers(3)
 
 			entry.WithFields(logrus.Fields{"stack": frames, "err": err}).Error("panic while serving request")
@@ -140,6 +177,9 @@ func (m *Middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next htt
 			json.NewEncoder(decorator).Encode(e)
 		}
 
+		if !logged && err == nil && decorator.StatusCode < http.StatusInternalServerError {
+			return
+		}
 		latency := m.clock.Since(start)
 		entry.WithFields(logrus.Fields{
 			"status": decorator.StatusCode,
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareSampleURL(t *testing.T) {
+	var out bytes.Buffer
+	logger := &logrus.Logger{Out: &out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+
+	mw := NewMiddleware(logger, "requestID")
+	require.NoError(t, mw.SampleURL("/health", NewSampler(1, 0, time.Hour)))
+
+	handler := mw.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	for _, target := range []string{"/health", "/health", "/health?fail=1", "/users", "/users"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.Equal(t, 1, strings.Count(out.String(), `"request":"/health","status":0`))
+	assert.Equal(t, 1, strings.Count(out.String(), `"request":"/health?fail=1","status":503`))
+	assert.Equal(t, 2, strings.Count(out.String(), `"request":"/users","status":0`))
+	assert.Equal(t, 1, strings.Count(out.String(), `"msg":"started handling request","remote":"192.0.2.1:1234","request":"/health"`))
+}
+
+func TestMiddlewareSampleURLDecidesOncePerRequest(t *testing.T) {
+	var out bytes.Buffer
+	logger := &logrus.Logger{Out: &out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+
+	sampler := NewSampler(1, 0, time.Hour)
+	mw := &Middleware{Logger: logger, logStarting: true, clock: &realClock{}}
+	require.NoError(t, mw.SampleURL("/health", sampler))
+
+	handler := mw.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for i := 0; i < 3; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+
+	assert.Equal(t, 1, strings.Count(out.String(), "started handling request"))
+	assert.Equal(t, 1, strings.Count(out.String(), "completed handling request"))
+	assert.Equal(t, map[string]int{"/health": 2}, sampler.Suppressed())
+}
+
+func TestMiddlewareContextLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := &logrus.Logger{Out: &out, Level: logrus.DebugLevel, Hooks: make(logrus.LevelHooks), Formatter: &logrus.JSONFormatter{}}
+
+	handler := NewMiddleware(logger, "requestID").Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		AddLogFields(r.Context(), logrus.Fields{"user": "john"})
+		LoggerFromContext(r.Context()).Info("handling")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("X-Request-Id", "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], `"msg":"handling"`)
+	assert.Contains(t, lines[1], `"user":"john","xRequestId":"req-1"`)
+	assert.Contains(t, lines[2], `"msg":"completed handling request"`)
+	assert.Contains(t, lines[2], `"user":"john","xRequestId":"req-1"`)
+}
@@ -0,0 +1,276 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SecretResolver resolves reference to the secret, reference is the value without the scheme prefix
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolverFunc is a function implementing SecretResolver
+type SecretResolverFunc func(ref string) (string, error)
+
+// Resolve calls f(ref)
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// minMaskedLength is the minimal length of the resolved secret masked by Secrets.Mask,
+// shorter values would mask unrelated text
+const minMaskedLength = 4
+
+// Secrets resolves values referencing secrets as <scheme>:<reference>, e.g. file:/run/secrets/db_pass,
+// with resolvers registered for the scheme. Values with unknown schemes are kept as is.
+// Resolved secrets are remembered to be masked in logs, see Hook
+type Secrets struct {
+	mu        sync.RWMutex
+	resolvers map[string]SecretResolver
+	resolved  map[string]struct{}
+}
+
+// NewSecrets returns Secrets with the file: resolver. Resolvers running commands or decrypting values
+// are registered explicitly:
+//
+//	secrets.Register("cmd", util.CommandSecretResolver(10*time.Second))
+//	secrets.Register("enc", util.EncryptedSecretResolver(key))
+func NewSecrets() *Secrets {
+	s := &Secrets{
+		resolvers: make(map[string]SecretResolver),
+		resolved:  make(map[string]struct{}),
+	}
+	s.Register("file", SecretResolverFunc(FileSecret))
+	return s
+}
+
+// Register sets resolver for references with scheme
+func (s *Secrets) Register(scheme string, resolver SecretResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resolvers[scheme] = resolver
+}
+
+// Resolve returns the secret value references, ok is false if value isn't a reference
+func (s *Secrets) Resolve(value string) (secret string, ok bool, err error) {
+	i := strings.Index(value, ":")
+	if i <= 0 {
+		return value, false, nil
+	}
+
+	s.mu.RLock()
+	resolver, found := s.resolvers[value[:i]]
+	s.mu.RUnlock()
+	if !found {
+		return value, false, nil
+	}
+
+	secret, err = resolver.Resolve(value[i+1:])
+	if err != nil {
+		return "", true, fmt.Errorf("resolve %s secret: %w", value[:i], err)
+	}
+
+	if len(secret) >= minMaskedLength {
+		s.mu.Lock()
+		s.resolved[secret] = struct{}{}
+		s.mu.Unlock()
+	}
+	return secret, true, nil
+}
+
+// ResolveAll replaces references in vars with secrets and returns keys of the replaced values
+func (s *Secrets) ResolveAll(vars map[string]string) ([]string, error) {
+	var keys []string
+	for key, value := range vars {
+		secret, ok, err := s.Resolve(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if ok {
+			vars[key] = secret
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Mask replaces resolved secrets in text
+func (s *Secrets) Mask(text string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for secret := range s.resolved {
+		text = strings.Replace(text, secret, maskedValue, -1)
+	}
+	return text
+}
+
+// Hook returns logrus hook masking resolved secrets in messages and string fields, e.g.
+//
+//	util.Log.AddHook(secrets.Hook())
+func (s *Secrets) Hook() logrus.Hook {
+	return &secretsHook{secrets: s}
+}
+
+type secretsHook struct {
+	secrets *Secrets
+}
+
+func (h *secretsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *secretsHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.secrets.Mask(entry.Message)
+
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		data[key] = value
+
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = v
+		case error:
+			text = v.Error()
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			continue
+		}
+		// values without secrets keep their type, e.g. durations stay numbers in JSON
+		if masked := h.secrets.Mask(text); masked != text {
+			data[key] = masked
+		}
+	}
+	entry.Data = data
+	return nil
+}
+
+// FileSecret reads secret from file at path, the trailing newline is trimmed
+func FileSecret(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// CommandSecretResolver returns resolver running the reference as command and returning its trimmed output,
+// e.g. cmd:pass show db. The command is split on spaces and run without shell.
+// Register it only for values coming from trusted sources:
+//
+//	secrets.Register("cmd", util.CommandSecretResolver(10*time.Second))
+func CommandSecretResolver(timeout time.Duration) SecretResolver {
+	return SecretResolverFunc(func(ref string) (string, error) {
+		args := strings.Fields(ref)
+		if len(args) == 0 {
+			return "", errors.New("empty command")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	})
+}
+
+const encryptedSecretVersion = "v1:"
+
+// EncryptedSecretResolver returns resolver for values encrypted by EncryptSecret with AES-GCM,
+// register it for the enc scheme:
+//
+//	secrets.Register("enc", util.EncryptedSecretResolver(key))
+func EncryptedSecretResolver(key []byte) SecretResolver {
+	return SecretResolverFunc(func(ref string) (string, error) {
+		if !strings.HasPrefix(ref, encryptedSecretVersion) {
+			return "", errors.New("unsupported encrypted secret version")
+		}
+
+		data, err := base64.StdEncoding.DecodeString(ref[len(encryptedSecretVersion):])
+		if err != nil {
+			return "", err
+		}
+
+		gcm, err := newGCM(key)
+		if err != nil {
+			return "", err
+		}
+		if len(data) < gcm.NonceSize() {
+			return "", errors.New("encrypted secret is too short")
+		}
+
+		plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+		if err != nil {
+			return "", err
+		}
+		return string(plain), nil
+	})
+}
+
+// EncryptSecret encrypts secret with AES-GCM and returns value with the enc: scheme resolved by EncryptedSecretResolver
+func EncryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	data := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return "enc:" + encryptedSecretVersion + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ReadSecretKey reads AES key from keyfile holding 16, 24 or 32 bytes raw or in base64
+func ReadSecretKey(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil && isAESKeySize(len(key)) {
+		return key, nil
+	}
+	if isAESKeySize(len(data)) {
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s: key must be 16, 24 or 32 bytes", path)
+}
+
+func isAESKeySize(n int) bool {
+	return n == 16 || n == 24 || n == 32
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretsResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := bytes.Repeat([]byte{7}, 32)
+	keyFile := writeTestFile(t, dir, "key", base64.StdEncoding.EncodeToString(key)+"\n")
+	passFile := writeTestFile(t, dir, "db_pass", "file-secret\n")
+
+	readKey, err := ReadSecretKey(keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, key, readKey)
+
+	encrypted, err := EncryptSecret(key, "encrypted-secret")
+	require.NoError(t, err)
+
+	secrets := NewSecrets()
+	secrets.Register("enc", EncryptedSecretResolver(readKey))
+
+	_, ok, err := secrets.Resolve("cmd:echo command-secret")
+	require.NoError(t, err)
+	assert.False(t, ok, "cmd resolver must be registered explicitly")
+	secrets.Register("cmd", CommandSecretResolver(time.Second))
+
+	cases := map[string]struct {
+		value  string
+		secret string
+		ok     bool
+	}{
+		"plain":          {value: "plain", secret: "plain"},
+		"unknown scheme": {value: "http://host", secret: "http://host"},
+		"file":           {value: "file:" + passFile, secret: "file-secret", ok: true},
+		"encrypted":      {value: encrypted, secret: "encrypted-secret", ok: true},
+		"command":        {value: "cmd:echo command-secret", secret: "command-secret", ok: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			secret, ok, err := secrets.Resolve(c.value)
+			require.NoError(t, err)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.secret, secret)
+		})
+	}
+
+	_, _, err = secrets.Resolve("enc:v1:" + base64.StdEncoding.EncodeToString([]byte("tampered value")))
+	assert.Error(t, err)
+	_, _, err = secrets.Resolve("file:" + dir + "/missing")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	assert.Equal(t, "password is ******", secrets.Mask("password is file-secret"))
+}
+
+func TestSecretsInConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	passFile := writeTestFile(t, dir, "pass", "s3cr3t")
+	secrets := NewSecrets()
+
+	cfg := NewConfig(DefaultsSource(map[string]string{"DB_PASS": "file:" + passFile, "HOST": "db", "OTHER": "file:" + passFile}))
+	cfg.SetSecrets(secrets, "DB_PASS")
+	require.NoError(t, cfg.Load())
+
+	value, _ := cfg.Lookup("DB_PASS")
+	assert.Equal(t, "s3cr3t", value)
+	value, _ = cfg.Lookup("OTHER")
+	assert.Equal(t, "file:"+passFile, value)
+	assert.Equal(t, ConfigValue{Value: maskedValue, Source: "defaults", Secret: true}, cfg.Report()["DB_PASS"])
+	assert.Equal(t, "db", cfg.Report()["HOST"].Value)
+
+	var s struct {
+		Pass  string `env:"PASS" secret:"true"`
+		Plain string `env:"PLAIN"`
+	}
+	lookup := MapLookup(map[string]string{"PASS": "file:" + passFile, "PLAIN": "file:" + passFile}, nil)
+	require.NoError(t, LoadConfig(&s, WithSecrets(secrets), WithLookup(lookup)))
+	assert.Equal(t, "s3cr3t", s.Pass)
+	assert.Equal(t, "file:"+passFile, s.Plain)
+
+	var out bytes.Buffer
+	logger := &logrus.Logger{Out: &out, Level: logrus.InfoLevel, Formatter: &logrus.JSONFormatter{}, Hooks: make(logrus.LevelHooks)}
+	logger.AddHook(secrets.Hook())
+	entry := logger.WithField("dsn", "postgres://user:s3cr3t@db")
+	entry.Info("connecting with s3cr3t")
+	assert.NotContains(t, out.String(), "s3cr3t")
+	assert.Contains(t, out.String(), "postgres://user:******@db")
+	assert.Equal(t, "postgres://user:s3cr3t@db", entry.Data["dsn"])
+
+	out.Reset()
+	logger.WithFields(logrus.Fields{"took": 1500 * time.Microsecond, "err": errors.New("bad s3cr3t")}).Info("done")
+	assert.Contains(t, out.String(), `"took":1500000`)
+	assert.Contains(t, out.String(), `"err":"bad ******"`)
+}